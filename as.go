@@ -0,0 +1,69 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterAs registers a constructor like Register but binds its value to the
+// interface the given pointer points to. The value is only injected as that
+// interface, not as the type the constructor returns.
+//
+//   container.RegisterAs((*Handler)(nil), NewThing)
+//
+// Bindings are the only way to inject an interface in a container created
+// with WithExplicitInterfaces.
+//
+//   container := injector.NewContainer(injector.WithExplicitInterfaces())
+//
+// RegisterAs panics if the type returned by the constructor does not
+// implement the interface.
+func (container *Container) RegisterAs(iface interface{}, _constructor interface{}) {
+	if err := container.RegisterAsE(iface, _constructor); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterAsE works like RegisterAs but returns an error instead of panicking
+// if the constructor cannot be bound to the interface.
+func (container *Container) RegisterAsE(iface interface{}, _constructor interface{}) error {
+	ifaceType := reflect.TypeOf(iface)
+
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("Binding '%s' must be a pointer to an interface", ifaceType)
+	}
+
+	ifaceType = ifaceType.Elem()
+
+	details, err := parseConstructor(_constructor)
+
+	if err != nil {
+		return err
+	}
+
+	if !details.ReturnType.Implements(ifaceType) {
+		return fmt.Errorf("Type '%s' returned by constructor '%s' does not implement '%s'", details.ReturnType, details.Function.Type(), ifaceType)
+	}
+
+	details.ReturnType = ifaceType
+	details.Decorator = false
+
+	for _, param := range details.Parameters {
+		if param == ifaceType {
+			details.Decorator = true
+		}
+	}
+
+	container.addConstructors(details)
+
+	return nil
+}
+
+// WithExplicitInterfaces disables matching interfaces against every
+// constructor whose value implements them. An interface is then only injected
+// from constructors bound to it with RegisterAs or returning it directly.
+func WithExplicitInterfaces() Option {
+	return func(container *Container) {
+		container.explicitInterfaces = true
+	}
+}
@@ -0,0 +1,99 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type AsApp struct {
+	Handler AsHandler
+}
+
+type AsConcreteApp struct {
+	Thing *AsThing
+}
+
+type AsHandler interface {
+	Handle() string
+}
+
+type AsThing struct {
+	value string
+}
+
+func (thing *AsThing) Handle() string {
+	return thing.value
+}
+
+type AsOther struct {
+}
+
+func NewAsThing() *AsThing {
+	return &AsThing{value: "thing"}
+}
+
+func NewAsOther() *AsOther {
+	return &AsOther{}
+}
+
+func TestRegisterAs(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterAs((*AsHandler)(nil), NewAsThing)
+
+	app := &AsApp{}
+	container.Resolve(app)
+
+	if app.Handler == nil || app.Handler.Handle() != "thing" {
+		t.Errorf("Handler could not be resolved")
+	}
+
+	if err := container.ResolveContext(context.Background(), &AsConcreteApp{}); err == nil {
+		t.Errorf("Bound constructor was injected as concrete type")
+	}
+}
+
+func TestRegisterAsNotImplemented(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Non-implementing binding was not rejected")
+		}
+	}()
+
+	container := NewContainer()
+
+	container.RegisterAs((*AsHandler)(nil), NewAsOther)
+}
+
+func TestExplicitInterfaces(t *testing.T) {
+	container := NewContainer(WithExplicitInterfaces())
+
+	container.Register(NewAsThing)
+
+	err := container.ResolveContext(context.Background(), &AsApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "bound explicitly") {
+		t.Errorf("Implicit interface match was not rejected: %v", err)
+	}
+
+	app := &AsConcreteApp{}
+	container.Resolve(app)
+
+	if app.Thing == nil {
+		t.Errorf("Thing could not be resolved")
+	}
+}
+
+func TestExplicitInterfacesBound(t *testing.T) {
+	container := NewContainer(WithExplicitInterfaces())
+
+	container.RegisterAs((*AsHandler)(nil), NewAsThing)
+
+	app := &AsApp{}
+	container.Resolve(app)
+
+	if app.Handler == nil || app.Handler.Handle() != "thing" {
+		t.Errorf("Bound handler could not be resolved")
+	}
+}
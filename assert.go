@@ -0,0 +1,53 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterWithAssertion registers a constructor like Register along with an
+// assertion that checks the invariants of the constructed value right after
+// the constructor returned. An error returned by the assertion fails the
+// resolution. The assertion must take a single parameter the return type of
+// the constructor is assignable to and return an error.
+//
+//   container.RegisterWithAssertion(NewConfig, func(config *Config) error {
+//     if config.Port == 0 {
+//       return errors.New("port must be set")
+//     }
+//
+//     return nil
+//   })
+//
+// Assertions of pure constructors also run during ValidateDeep.
+func (container *Container) RegisterWithAssertion(_constructor interface{}, assertion interface{}) {
+	details := newConstructor(_constructor)
+
+	assertionType := reflect.TypeOf(assertion)
+
+	if assertionType == nil || assertionType.Kind() != reflect.Func || assertionType.NumIn() != 1 || assertionType.NumOut() != 1 || assertionType.Out(0) != errorType {
+		panic(fmt.Errorf("Assertion '%s' must be a function with a single parameter returning an error", assertionType))
+	}
+
+	if !details.ReturnType.AssignableTo(assertionType.In(0)) {
+		panic(fmt.Errorf("Assertion '%s' does not accept type '%s'", assertionType, details.ReturnType))
+	}
+
+	details.Assertion = reflect.ValueOf(assertion)
+
+	container.addConstructors(details)
+}
+
+// assert calls the assertion of the constructor, if any, with the value it
+// constructed.
+func (_resolver *resolver) assert(constructor *constructor, value reflect.Value) error {
+	if !constructor.Assertion.IsValid() {
+		return nil
+	}
+
+	if err, _ := constructor.Assertion.Call([]reflect.Value{value})[0].Interface().(error); err != nil {
+		return fmt.Errorf("Assertion of constructor '%s' failed for type '%s': %w", constructor.Function.Type(), constructor.ReturnType, err)
+	}
+
+	return nil
+}
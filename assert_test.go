@@ -0,0 +1,67 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type AssertConfig struct {
+	Port int
+}
+
+type AssertApp struct {
+	Config *AssertConfig
+}
+
+func TestAssertion(t *testing.T) {
+	invalid := errors.New("port must be set")
+
+	assertion := func(config *AssertConfig) error {
+		if config.Port == 0 {
+			return invalid
+		}
+
+		return nil
+	}
+
+	container := NewContainer()
+
+	container.RegisterWithAssertion(func() *AssertConfig {
+		return &AssertConfig{Port: 8080}
+	}, assertion)
+
+	var app AssertApp
+	container.Resolve(&app)
+
+	if app.Config == nil || app.Config.Port != 8080 {
+		t.Errorf("AssertApp could not be resolved")
+	}
+
+	container = NewContainer()
+
+	container.RegisterWithAssertion(func() *AssertConfig {
+		return &AssertConfig{}
+	}, assertion)
+
+	var rejected AssertApp
+
+	err := container.ResolveContext(context.Background(), &rejected)
+
+	if !errors.Is(err, invalid) || !strings.HasPrefix(err.Error(), "Assertion of constructor 'func() *injector.AssertConfig' failed") {
+		t.Errorf("Assertion did not reject the config: %v", err)
+	}
+
+	if rejected.Config != nil {
+		t.Errorf("Rejected config was injected")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Assertion without error result was accepted")
+		}
+	}()
+
+	container.RegisterWithAssertion(func() *AssertConfig { return nil }, func(*AssertConfig) {})
+}
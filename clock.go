@@ -0,0 +1,47 @@
+package injector
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clock tells the current time. Constructors taking a Clock receive the clock
+// of the container unless a constructor for Clock is registered.
+//
+//   func NewSession(clock injector.Clock) *Session {…}
+type Clock interface {
+	Now() time.Time
+}
+
+var clockType = reflect.TypeOf((*Clock)(nil)).Elem()
+
+type realClock struct {
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock sets the clock injected for Clock, e.g. a fake clock returning a
+// fixed time in tests. The real time is used by default.
+func WithClock(clock Clock) Option {
+	return func(container *Container) {
+		container.clock = clock
+	}
+}
+
+// builtin returns the value the container provides itself for a type that
+// has no registered constructor.
+func (container *Container) builtin(_type reflect.Type) (reflect.Value, bool) {
+	if _type != clockType || len(container.findConstructors(_type)) > 0 {
+		return reflect.Value{}, false
+	}
+
+	var clock Clock = realClock{}
+
+	if container.clock != nil {
+		clock = container.clock
+	}
+
+	return reflect.ValueOf(&clock).Elem(), true
+}
@@ -0,0 +1,58 @@
+package injector
+
+import (
+	"testing"
+	"time"
+)
+
+type ClockApp struct {
+	Session *ClockSession
+}
+
+type ClockSession struct {
+	started time.Time
+}
+
+type ClockFake struct {
+	now time.Time
+}
+
+func (clock *ClockFake) Now() time.Time {
+	return clock.now
+}
+
+func NewClockSession(clock Clock) *ClockSession {
+	return &ClockSession{
+		started: clock.Now(),
+	}
+}
+
+func TestClock(t *testing.T) {
+	fixed := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	container := NewContainer(WithClock(&ClockFake{now: fixed}))
+
+	container.Register(NewClockSession)
+
+	app := &ClockApp{}
+	container.Resolve(app)
+
+	if !app.Session.started.Equal(fixed) {
+		t.Errorf("Fake clock was not injected")
+	}
+}
+
+func TestClockDefault(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewClockSession)
+
+	before := time.Now()
+
+	app := &ClockApp{}
+	container.Resolve(app)
+
+	if app.Session.started.Before(before) {
+		t.Errorf("Real clock was not injected")
+	}
+}
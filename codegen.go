@@ -0,0 +1,339 @@
+package injector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// GenerateCode emits the source of a Go function that performs the wiring of
+// all registered constructors with explicit calls in dependency order, so no
+// reflection is needed at runtime.
+//
+// The pkg parameter is the import path of the package the code is generated
+// for. Constructors and types of that package are referenced without a
+// qualifier. The function returns the values of all constructors that are not
+// used as a dependency of another constructor in registration order.
+//
+//   func InitializeApp() *App {
+//     v0 := NewFoo()
+//     v1 := NewApp(v0)
+//     return v1
+//   }
+//
+// Only named top-level functions can be generated, instances, function
+// literals and constructors returning a cleanup function or an error cause an
+// error.
+func (container *Container) GenerateCode(pkg, funcName string) (string, error) {
+	resolver := newResolver(context.Background(), container)
+	resolver.DryRun = true
+
+	for _, constructor := range container.allConstructors() {
+		// Variants of other modes are ignored like by the resolver
+		if !container.activeVariant(constructor) {
+			continue
+		}
+
+		if constructor.Instance.IsValid() {
+			return "", fmt.Errorf("Instance of type '%s' cannot be generated", constructor.ReturnType)
+		}
+
+		if constructor.Decorator {
+			return "", fmt.Errorf("Decorator '%s' cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.Cleanup {
+			return "", fmt.Errorf("Constructor '%s' returning a cleanup function cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.Transient {
+			return "", fmt.Errorf("Transient constructor '%s' cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.Deferred {
+			return "", fmt.Errorf("Deferred constructor '%s' cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.ReturnsError {
+			return "", fmt.Errorf("Constructor '%s' returning an error cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.Assertion.IsValid() {
+			return "", fmt.Errorf("Constructor '%s' with an assertion cannot be generated", constructor.Function.Type())
+		}
+
+		if constructor.Hook.IsValid() {
+			return "", fmt.Errorf("Constructor '%s' with a hook cannot be generated", constructor.Function.Type())
+		}
+
+		if len(constructor.Defaults) > 0 {
+			return "", fmt.Errorf("Constructor '%s' with defaults cannot be generated", constructor.Function.Type())
+		}
+
+		// Keys are computed from the constructed values
+		for _, param := range constructor.Parameters {
+			if _, ok := container.keyByType[innerType(param)]; ok && param.Kind() == reflect.Slice {
+				return "", fmt.Errorf("Deduplicated parameter '%s' of constructor '%s' cannot be generated", param, constructor.Function.Type())
+			}
+		}
+
+		if _, err := resolver.invokeConstructor(constructor, constructor.ReturnType); err != nil {
+			return "", err
+		}
+	}
+
+	generator := &generator{
+		Package: pkg,
+		Imports: make(map[string]string),
+	}
+
+	return generator.generate(resolver, funcName)
+}
+
+type generator struct {
+	Package string
+	Imports map[string]string
+}
+
+func (_generator *generator) generate(_resolver *resolver, funcName string) (string, error) {
+	var body bytes.Buffer
+
+	variables := make(map[*constructor]string)
+	dependencies := make(map[*constructor]bool)
+
+	for i, constructor := range _resolver.InvokedConstructors {
+		function, err := _generator.functionName(constructor)
+
+		if err != nil {
+			return "", err
+		}
+
+		var arguments []string
+
+		for i, param := range constructor.Parameters {
+			if constructor.Dependencies[i].tagged() {
+				return "", fmt.Errorf("Tagged parameter '%s' of constructor '%s' cannot be generated", param, constructor.Function.Type())
+			}
+
+			sources := _resolver.ConstructorsByType[innerType(param)]
+
+			// Filtered members are left out like by the resolver
+			if param.Kind() == reflect.Slice {
+				sources = _resolver.Container.filterMembers(param.Elem(), sources)
+			}
+
+			var names []string
+
+			for _, source := range sources {
+				names = append(names, variables[source])
+				dependencies[source] = true
+			}
+
+			if param.Kind() != reflect.Slice {
+				if len(names) != 1 {
+					return "", fmt.Errorf("Parameter '%s' of constructor '%s' cannot be generated", param, constructor.Function.Type())
+				}
+
+				arguments = append(arguments, names[0])
+				continue
+			}
+
+			if _resolver.Container.sorted(param.Elem()) {
+				return "", fmt.Errorf("Sorted parameter '%s' of constructor '%s' cannot be generated", param, constructor.Function.Type())
+			}
+
+			sliceType, err := _generator.typeName(param)
+
+			if err != nil {
+				return "", err
+			}
+
+			arguments = append(arguments, fmt.Sprintf("%s{%s}", sliceType, strings.Join(names, ", ")))
+		}
+
+		variables[constructor] = fmt.Sprintf("v%d", i)
+
+		fmt.Fprintf(&body, "%s := %s(%s)\n", variables[constructor], function, strings.Join(arguments, ", "))
+	}
+
+	var results, returns []string
+
+	for _, constructor := range _resolver.Container.allConstructors() {
+		if dependencies[constructor] || !_resolver.Container.activeVariant(constructor) {
+			continue
+		}
+
+		returnType, err := _generator.typeName(constructor.ReturnType)
+
+		if err != nil {
+			return "", err
+		}
+
+		results = append(results, returnType)
+		returns = append(returns, variables[constructor])
+	}
+
+	var source bytes.Buffer
+
+	fmt.Fprintf(&source, "// Code generated by injector. DO NOT EDIT.\n\npackage %s\n\n", packageName(_generator.Package))
+
+	if len(_generator.Imports) > 0 {
+		var paths []string
+
+		for importPath := range _generator.Imports {
+			paths = append(paths, importPath)
+		}
+
+		sort.Strings(paths)
+
+		fmt.Fprintf(&source, "import (\n")
+
+		for _, importPath := range paths {
+			fmt.Fprintf(&source, "%s %q\n", _generator.Imports[importPath], importPath)
+		}
+
+		fmt.Fprintf(&source, ")\n\n")
+	}
+
+	fmt.Fprintf(&source, "func %s() (%s) {\n", funcName, strings.Join(results, ", "))
+	source.Write(body.Bytes())
+	fmt.Fprintf(&source, "return %s\n}\n", strings.Join(returns, ", "))
+
+	formatted, err := format.Source(source.Bytes())
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(formatted), nil
+}
+
+// functionName returns the qualified name of a constructor that is a
+// top-level function.
+func (_generator *generator) functionName(constructor *constructor) (string, error) {
+	name := runtime.FuncForPC(constructor.Function.Pointer()).Name()
+
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+
+	if dot < 0 {
+		return "", fmt.Errorf("Constructor '%s' is not a named function", constructor.Function.Type())
+	}
+
+	importPath := name[:slash+1+dot]
+	function := name[slash+1+dot+1:]
+
+	if strings.ContainsAny(function, ".()[]") {
+		return "", fmt.Errorf("Constructor '%s' (%s) is not a named top-level function", constructor.Function.Type(), name)
+	}
+
+	if importPath != _generator.Package && !token.IsExported(function) {
+		return "", fmt.Errorf("Constructor '%s' (%s) is unexported and cannot be referenced from package '%s'", constructor.Function.Type(), name, _generator.Package)
+	}
+
+	return _generator.qualify(importPath, function), nil
+}
+
+// typeName renders a type as Go source.
+func (_generator *generator) typeName(_type reflect.Type) (string, error) {
+	if _type.Name() != "" {
+		if strings.Contains(_type.Name(), "[") {
+			return "", fmt.Errorf("Generic type '%s' cannot be generated", _type)
+		}
+
+		if _type.PkgPath() != "" && _type.PkgPath() != _generator.Package && !token.IsExported(_type.Name()) {
+			return "", fmt.Errorf("Type '%s' is unexported and cannot be referenced from package '%s'", _type, _generator.Package)
+		}
+
+		return _generator.qualify(_type.PkgPath(), _type.Name()), nil
+	}
+
+	switch _type.Kind() {
+	case reflect.Ptr:
+		elem, err := _generator.typeName(_type.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := _generator.typeName(_type.Elem())
+		return "[]" + elem, err
+	case reflect.Array:
+		elem, err := _generator.typeName(_type.Elem())
+		return fmt.Sprintf("[%d]%s", _type.Len(), elem), err
+	case reflect.Chan:
+		elem, err := _generator.typeName(_type.Elem())
+
+		switch _type.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem, err
+		case reflect.SendDir:
+			return "chan<- " + elem, err
+		}
+
+		return "chan " + elem, err
+	case reflect.Map:
+		key, err := _generator.typeName(_type.Key())
+
+		if err != nil {
+			return "", err
+		}
+
+		elem, err := _generator.typeName(_type.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), err
+	case reflect.Interface:
+		if _type.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	}
+
+	return "", fmt.Errorf("Type '%s' cannot be generated", _type)
+}
+
+func (_generator *generator) qualify(importPath, name string) string {
+	if importPath == "" || importPath == _generator.Package {
+		return name
+	}
+
+	alias, ok := _generator.Imports[importPath]
+
+	if !ok {
+		alias = packageName(importPath)
+
+		// Make the alias unique among the imports used so far
+		for i := 2; _generator.aliasUsed(alias); i++ {
+			alias = fmt.Sprintf("%s%d", packageName(importPath), i)
+		}
+
+		_generator.Imports[importPath] = alias
+	}
+
+	return alias + "." + name
+}
+
+func (_generator *generator) aliasUsed(alias string) bool {
+	for _, used := range _generator.Imports {
+		if used == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+// packageName derives a valid package name from an import path.
+func packageName(importPath string) string {
+	name := []rune(path.Base(importPath))
+
+	for i, r := range name {
+		if !(r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i > 0 && '0' <= r && r <= '9') {
+			name[i] = '_'
+		}
+	}
+
+	return string(name)
+}
@@ -0,0 +1,291 @@
+package injector
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type GenerateFoo struct {
+	value string
+}
+
+type GenerateBar struct {
+	foo *GenerateFoo
+}
+
+type GenerateBaz struct {
+	foo *GenerateFoo
+	bar *GenerateBar
+	qux []GenerateQux
+}
+
+type GenerateQux interface {
+	Qux() string
+}
+
+func (foo *GenerateFoo) Qux() string {
+	return foo.value
+}
+
+func NewGenerateFoo() *GenerateFoo {
+	return &GenerateFoo{
+		value: "foo",
+	}
+}
+
+func NewGenerateBar(foo *GenerateFoo) *GenerateBar {
+	return &GenerateBar{
+		foo: foo,
+	}
+}
+
+func NewGenerateBaz(foo *GenerateFoo, bar *GenerateBar, qux []GenerateQux) *GenerateBaz {
+	return &GenerateBaz{
+		foo: foo,
+		bar: bar,
+		qux: qux,
+	}
+}
+
+// generateSource mirrors the declarations above for compiling the generated
+// code in isolation.
+const generateSource = `
+type GenerateFoo struct {
+	value string
+}
+
+type GenerateBar struct {
+	foo *GenerateFoo
+}
+
+type GenerateBaz struct {
+	foo *GenerateFoo
+	bar *GenerateBar
+	qux []GenerateQux
+}
+
+type GenerateQux interface {
+	Qux() string
+}
+
+func (foo *GenerateFoo) Qux() string {
+	return foo.value
+}
+
+func NewGenerateFoo() *GenerateFoo {
+	return &GenerateFoo{
+		value: "foo",
+	}
+}
+
+func NewGenerateBar(foo *GenerateFoo) *GenerateBar {
+	return &GenerateBar{
+		foo: foo,
+	}
+}
+
+func NewGenerateBaz(foo *GenerateFoo, bar *GenerateBar, qux []GenerateQux) *GenerateBaz {
+	return &GenerateBaz{
+		foo: foo,
+		bar: bar,
+		qux: qux,
+	}
+}
+`
+
+const generateTestSource = `
+import "testing"
+
+func TestGenerated(t *testing.T) {
+	baz := InitializeBaz()
+
+	if baz.foo == nil || baz.bar.foo != baz.foo || len(baz.qux) != 1 || baz.qux[0] != baz.foo {
+		t.Errorf("Baz was not wired")
+	}
+}
+`
+
+func TestGenerateCode(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewGenerateBaz, NewGenerateBar, NewGenerateFoo)
+
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	code, err := container.GenerateCode(pkg, "InitializeBaz")
+
+	if err != nil {
+		t.Fatalf("Code could not be generated: %s", err)
+	}
+
+	if !strings.Contains(code, "func InitializeBaz() *GenerateBaz {") {
+		t.Errorf("Unexpected code generated:\n%s", code)
+	}
+
+	if testing.Short() {
+		t.Skip("Skipping compilation of generated code in short mode")
+	}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("Skipping compilation of generated code without go tool")
+	}
+
+	dir := t.TempDir()
+	packageClause := "package " + packageName(pkg) + "\n"
+
+	files := map[string]string{
+		"go.mod":            "module generated\n",
+		"types.go":          packageClause + generateSource,
+		"generated.go":      code,
+		"generated_test.go": packageClause + generateTestSource,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	command := exec.Command("go", "test", ".")
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=", "GOPROXY=off")
+
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Errorf("Generated code failed: %s\n%s\n%s", err, output, code)
+	}
+}
+
+func TestGenerateCodeInstance(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterInstance(&GenerateFoo{})
+
+	if _, err := container.GenerateCode("main", "Initialize"); err == nil {
+		t.Errorf("Instance was not rejected")
+	}
+}
+
+type generateHidden struct {
+}
+
+func newGenerateHidden() *generateHidden {
+	return &generateHidden{}
+}
+
+func NewGenerateHidden() *generateHidden {
+	return &generateHidden{}
+}
+
+func TestGenerateCodeUnexported(t *testing.T) {
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	container := NewContainer()
+	container.Register(newGenerateHidden)
+
+	if _, err := container.GenerateCode(pkg, "Initialize"); err != nil {
+		t.Errorf("Unexported constructor of the same package was rejected: %s", err)
+	}
+
+	if _, err := container.GenerateCode("example.com/other", "Initialize"); err == nil || !strings.Contains(err.Error(), "unexported") {
+		t.Errorf("Unexported constructor of another package was not rejected: %v", err)
+	}
+
+	container = NewContainer()
+	container.Register(NewGenerateHidden)
+
+	if _, err := container.GenerateCode("example.com/other", "Initialize"); err == nil || !strings.Contains(err.Error(), "generateHidden") {
+		t.Errorf("Unexported type of another package was not rejected: %v", err)
+	}
+}
+
+func TestGenerateCodeFunctionLiteral(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *GenerateFoo {
+		return &GenerateFoo{}
+	})
+
+	if _, err := container.GenerateCode("main", "Initialize"); err == nil {
+		t.Errorf("Function literal was not rejected")
+	}
+}
+
+func NewGenerateTestingFoo() *GenerateFoo {
+	return &GenerateFoo{
+		value: "testing",
+	}
+}
+
+func TestGenerateCodeVariant(t *testing.T) {
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	container := NewContainer(WithMode("production"))
+
+	container.Register(NewGenerateBar)
+	container.RegisterVariant("production", NewGenerateFoo)
+	container.RegisterVariant("testing", NewGenerateTestingFoo)
+
+	code, err := container.GenerateCode(pkg, "Initialize")
+
+	if err != nil {
+		t.Fatalf("Code could not be generated: %s", err)
+	}
+
+	if !strings.Contains(code, "NewGenerateFoo()") || strings.Contains(code, "NewGenerateTestingFoo") {
+		t.Errorf("Inactive variant was generated:\n%s", code)
+	}
+
+	if !strings.Contains(code, "func Initialize() *GenerateBar {") {
+		t.Errorf("Unexpected code generated:\n%s", code)
+	}
+}
+
+func TestGenerateCodeHook(t *testing.T) {
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	container := NewContainer()
+
+	container.RegisterWithHook(NewGenerateFoo, func(*GenerateFoo) {})
+
+	if _, err := container.GenerateCode(pkg, "Initialize"); err == nil || !strings.Contains(err.Error(), "hook") {
+		t.Errorf("Hooked constructor was not rejected: %v", err)
+	}
+}
+
+func TestGenerateCodeFiltered(t *testing.T) {
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	container := NewContainer(FilterBy[GenerateQux](func(registration Registration) bool {
+		return registration.Name == "qux"
+	}))
+
+	container.Register(NewGenerateBaz, NewGenerateBar, NewGenerateFoo)
+
+	code, err := container.GenerateCode(pkg, "InitializeBaz")
+
+	if err != nil {
+		t.Fatalf("Code could not be generated: %s", err)
+	}
+
+	if !strings.Contains(code, "NewGenerateBaz(v0, v1, []GenerateQux{})") {
+		t.Errorf("Filtered member was generated:\n%s", code)
+	}
+}
+
+func TestGenerateCodeDeduplicated(t *testing.T) {
+	pkg := reflect.TypeOf(GenerateFoo{}).PkgPath()
+
+	container := NewContainer(DeduplicateBy(func(qux GenerateQux) string {
+		return qux.Qux()
+	}))
+
+	container.Register(NewGenerateBaz, NewGenerateBar, NewGenerateFoo)
+
+	if _, err := container.GenerateCode(pkg, "InitializeBaz"); err == nil || !strings.Contains(err.Error(), "Deduplicated parameter") {
+		t.Errorf("Deduplicated slice was not rejected: %v", err)
+	}
+}
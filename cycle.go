@@ -0,0 +1,64 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Cycle describes a cycle detected while resolving. Path holds the return
+// types of the constructors forming the cycle, starting with the constructor
+// that is requested again as Type.
+type Cycle struct {
+	Type reflect.Type
+	Path []reflect.Type
+}
+
+// WithCycleHandler registers a handler that is called for every cycle before
+// it is reported. The handler may break the cycle by returning a value for
+// the type requested again, e.g. a lazy proxy that forwards to the real value
+// once it is constructed. Returning false reports the cycle.
+//
+//   injector.NewContainer(injector.WithCycleHandler(func(cycle injector.Cycle) (interface{}, bool) {
+//     if cycle.Type == reflect.TypeOf((*UserService)(nil)).Elem() {
+//       return proxy, true
+//     }
+//
+//     return nil, false
+//   }))
+func WithCycleHandler(handler func(Cycle) (interface{}, bool)) Option {
+	return func(container *Container) {
+		container.cycleHandler = handler
+	}
+}
+
+// breakCycle asks the cycle handler for a value of the type requested again
+// by the pending constructors. It reports false if the cycle is not broken.
+func (_resolver *resolver) breakCycle(pending []*constructor, _type reflect.Type) (reflect.Value, bool, error) {
+	handler := _resolver.Container.cycleHandler
+
+	if handler == nil {
+		return reflect.Value{}, false, nil
+	}
+
+	cycle := Cycle{
+		Type: _type,
+	}
+
+	for _, constructor := range pending {
+		cycle.Path = append(cycle.Path, constructor.ReturnType)
+	}
+
+	breaker, ok := handler(cycle)
+
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	value := reflect.ValueOf(breaker)
+
+	if !value.IsValid() || !value.Type().AssignableTo(_type) {
+		return reflect.Value{}, true, fmt.Errorf("Value '%T' breaking the cycle is not assignable to type '%s'", breaker, _type)
+	}
+
+	return value, true, nil
+}
@@ -0,0 +1,87 @@
+package injector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type CycleHandlerApp struct {
+	Users CycleHandlerUsers
+}
+
+type CycleHandlerUsers interface {
+	Name() string
+}
+
+type CycleHandlerUserService struct {
+	notifier *CycleHandlerNotifier
+}
+
+func (service *CycleHandlerUserService) Name() string {
+	return "users"
+}
+
+type CycleHandlerNotifier struct {
+	users CycleHandlerUsers
+}
+
+type CycleHandlerUsersProxy struct {
+	target CycleHandlerUsers
+}
+
+func (proxy *CycleHandlerUsersProxy) Name() string {
+	return proxy.target.Name()
+}
+
+func NewCycleHandlerUserService(notifier *CycleHandlerNotifier) *CycleHandlerUserService {
+	return &CycleHandlerUserService{
+		notifier: notifier,
+	}
+}
+
+func NewCycleHandlerNotifier(users CycleHandlerUsers) *CycleHandlerNotifier {
+	return &CycleHandlerNotifier{
+		users: users,
+	}
+}
+
+func TestCycleHandler(t *testing.T) {
+	proxy := &CycleHandlerUsersProxy{}
+
+	var cycles []Cycle
+
+	container := NewContainer(WithCycleHandler(func(cycle Cycle) (interface{}, bool) {
+		cycles = append(cycles, cycle)
+		return proxy, true
+	}))
+
+	container.Register(NewCycleHandlerUserService, NewCycleHandlerNotifier)
+
+	app := &CycleHandlerApp{}
+	container.Resolve(app)
+
+	proxy.target = app.Users
+
+	expected := []reflect.Type{reflect.TypeOf(&CycleHandlerUserService{}), reflect.TypeOf(&CycleHandlerNotifier{})}
+
+	if len(cycles) != 1 || cycles[0].Type != reflect.TypeOf((*CycleHandlerUsers)(nil)).Elem() || !reflect.DeepEqual(cycles[0].Path, expected) {
+		t.Errorf("Unexpected cycles %v", cycles)
+	}
+
+	if app.Users.(*CycleHandlerUserService).notifier.users.Name() != "users" {
+		t.Errorf("Cycle was not broken by the proxy")
+	}
+}
+
+func TestCycleHandlerDeclined(t *testing.T) {
+	container := NewContainer(WithCycleHandler(func(cycle Cycle) (interface{}, bool) {
+		return nil, false
+	}))
+
+	container.Register(NewCycleHandlerUserService, NewCycleHandlerNotifier)
+
+	if err := container.ResolveContext(context.Background(), &CycleHandlerApp{}); err == nil {
+		t.Errorf("Declined cycle was not reported")
+	}
+}
@@ -0,0 +1,54 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// provide returns the value of the constructor for the requested type with
+// all applicable decorators applied.
+func (_resolver *resolver) provide(constructor *constructor, _type reflect.Type) (reflect.Value, error) {
+	value, err := _resolver.invokeConstructor(constructor, _type)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return _resolver.decorate(constructor, value, _type)
+}
+
+// decorate passes the value of the base constructor through all decorators
+// that accept it and whose result still satisfies the requested type.
+func (_resolver *resolver) decorate(base *constructor, value reflect.Value, _type reflect.Type) (reflect.Value, error) {
+	for _, decorator := range _resolver.Container.allConstructors() {
+		if !decorator.Decorator {
+			continue
+		}
+
+		decoratedType := decorator.ReturnType
+
+		if !base.ReturnType.AssignableTo(decoratedType) || !decoratedType.AssignableTo(_type) {
+			continue
+		}
+
+		if other, ok := _resolver.DecoratedBases[decorator]; ok && other != base {
+			return reflect.Value{}, fmt.Errorf(
+				"Decorator '%s' cannot decorate both '%s' and '%s'",
+				decorator.Function.Type(), other.Function.Type(), base.Function.Type(),
+			)
+		}
+
+		_resolver.DecoratedBases[decorator] = base
+		_resolver.DecoratedValues[decorator] = value
+
+		decorated, err := _resolver.invokeConstructor(decorator, decoratedType)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value = decorated
+	}
+
+	return value, nil
+}
@@ -0,0 +1,184 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type DecoratorApp struct {
+	Service *DecoratorService
+	Repo    DecoratorRepo
+}
+
+type DecoratorService struct {
+	repo DecoratorRepo
+}
+
+type DecoratorRepo interface {
+	Find() string
+}
+
+type DecoratorBaseRepo struct {
+}
+
+func (repo *DecoratorBaseRepo) Find() string {
+	return "base"
+}
+
+type DecoratorCachingRepo struct {
+	inner DecoratorRepo
+}
+
+func (repo *DecoratorCachingRepo) Find() string {
+	return "cached(" + repo.inner.Find() + ")"
+}
+
+type DecoratorLoggingRepo struct {
+	inner DecoratorRepo
+}
+
+func (repo *DecoratorLoggingRepo) Find() string {
+	return "logged(" + repo.inner.Find() + ")"
+}
+
+func NewDecoratorService(repo DecoratorRepo) *DecoratorService {
+	return &DecoratorService{
+		repo: repo,
+	}
+}
+
+func NewDecoratorBaseRepo() *DecoratorBaseRepo {
+	return &DecoratorBaseRepo{}
+}
+
+func NewDecoratorCachingRepo(inner DecoratorRepo) DecoratorRepo {
+	return &DecoratorCachingRepo{
+		inner: inner,
+	}
+}
+
+func NewDecoratorLoggingRepo(inner DecoratorRepo) DecoratorRepo {
+	return &DecoratorLoggingRepo{
+		inner: inner,
+	}
+}
+
+func TestDecorator(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewDecoratorCachingRepo, NewDecoratorService, NewDecoratorBaseRepo)
+
+	app := &DecoratorApp{}
+	container.Resolve(app)
+
+	if app.Service.repo.Find() != "cached(base)" {
+		t.Errorf("Repo could not be decorated")
+	}
+
+	if app.Repo != app.Service.repo {
+		t.Errorf("Decorated repo is not a singleton")
+	}
+}
+
+func TestDecoratorChain(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewDecoratorService, NewDecoratorBaseRepo, NewDecoratorCachingRepo, NewDecoratorLoggingRepo)
+
+	app := &DecoratorApp{}
+	container.Resolve(app)
+
+	if app.Service.repo.Find() != "logged(cached(base))" {
+		t.Errorf("Repo could not be decorated")
+	}
+}
+
+func TestDecoratorConcrete(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewDecoratorBaseRepo, NewDecoratorCachingRepo)
+
+	app := &struct {
+		Repo *DecoratorBaseRepo
+	}{}
+
+	container.Resolve(app)
+
+	if app.Repo == nil {
+		t.Errorf("Repo could not be resolved")
+	}
+}
+
+type SelfReferenceApp struct {
+	Node *SelfReferenceNode
+}
+
+type SelfReferenceNode struct {
+	next *SelfReferenceNode
+}
+
+func TestSelfReference(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func(next *SelfReferenceNode) *SelfReferenceNode {
+		return &SelfReferenceNode{next: next}
+	})
+
+	err := container.ResolveContext(context.Background(), &SelfReferenceApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "Self-cycle detected") {
+		t.Errorf("Self-referential constructor was not detected: %v", err)
+	}
+}
+
+type DecoratorWrappedClient struct {
+	inner *DecoratorWrappedClient
+}
+
+func (client *DecoratorWrappedClient) Find() string {
+	if client.inner != nil {
+		return "wrapped(" + client.inner.Find() + ")"
+	}
+
+	return "base"
+}
+
+type DecoratorWrappedApp struct {
+	Repo   DecoratorRepo
+	Client *DecoratorWrappedClient
+}
+
+type DecoratorWrappedReversedApp struct {
+	Client *DecoratorWrappedClient
+	Repo   DecoratorRepo
+}
+
+func TestDecoratorInterfaceConsumer(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		func() *DecoratorWrappedClient { return &DecoratorWrappedClient{} },
+		func(inner *DecoratorWrappedClient) *DecoratorWrappedClient {
+			return &DecoratorWrappedClient{inner: inner}
+		},
+	)
+
+	app := &DecoratorWrappedApp{}
+	container.Resolve(app)
+
+	if app.Repo == nil || app.Repo.Find() != "wrapped(base)" {
+		t.Errorf("Interface consumer did not receive the decorated value")
+	}
+
+	if app.Client == nil || app.Repo != DecoratorRepo(app.Client) {
+		t.Errorf("Concrete consumer did not receive the same decorated value")
+	}
+
+	reversed := &DecoratorWrappedReversedApp{}
+	container.Resolve(reversed)
+
+	if reversed.Client == nil || reversed.Client.Find() != "wrapped(base)" || reversed.Repo != DecoratorRepo(reversed.Client) {
+		t.Errorf("Consumers resolved in reverse order did not receive the same decorated value")
+	}
+}
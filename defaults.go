@@ -0,0 +1,73 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterWithDefaults registers a constructor like Register along with
+// default functions for some of its parameters, keyed by the position of the
+// parameter. A default is only used if the container provides nothing for the
+// parameter, it never hides an ambiguity or a failing dependency.
+//
+//   container.RegisterWithDefaults(NewServer, map[int]func() interface{}{
+//     1: func() interface{} { return &ServerOptions{Port: 8080} },
+//   })
+//
+// A default returning nil injects the zero value of the parameter.
+func (container *Container) RegisterWithDefaults(_constructor interface{}, defaults map[int]func() interface{}) {
+	details := newConstructor(_constructor)
+
+	for position, _default := range defaults {
+		if position < 0 || position >= len(details.Parameters) {
+			panic(fmt.Errorf("Default for position %d of constructor '%s' is out of range", position, details.Function.Type()))
+		}
+
+		if _default == nil {
+			panic(fmt.Errorf("Default for position %d of constructor '%s' must not be nil", position, details.Function.Type()))
+		}
+	}
+
+	details.Defaults = defaults
+
+	container.addConstructors(details)
+}
+
+// resolveDefault returns the default value of the parameter at the given
+// position if the parameter could not be resolved because nothing provides
+// it. Otherwise the error of the resolution is returned.
+func (_resolver *resolver) resolveDefault(constructor *constructor, position int, err error) (reflect.Value, error) {
+	_default, ok := constructor.Defaults[position]
+
+	if !ok {
+		return reflect.Value{}, err
+	}
+
+	dependency := constructor.Dependencies[position]
+
+	if constructors, findErr := _resolver.Container.findDependencyConstructors(dependency); findErr != nil || len(constructors) > 0 {
+		return reflect.Value{}, err
+	}
+
+	param := constructor.Parameters[position]
+
+	// Defaults are not invoked during a dry run
+	if _resolver.DryRun {
+		return _resolver.placeholder(param), nil
+	}
+
+	value := reflect.ValueOf(_default())
+
+	if !value.IsValid() {
+		return reflect.Zero(param), nil
+	}
+
+	if !value.Type().AssignableTo(param) {
+		return reflect.Value{}, fmt.Errorf(
+			"Default for position %d of constructor '%s' returned type '%s' which is not assignable to '%s'",
+			position, constructor.Function.Type(), value.Type(), param,
+		)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,69 @@
+package injector
+
+import (
+	"testing"
+)
+
+type DefaultsConfig struct{}
+
+type DefaultsOptions struct {
+	Port int
+}
+
+type DefaultsServer struct {
+	config  *DefaultsConfig
+	options *DefaultsOptions
+}
+
+type DefaultsApp struct {
+	Server *DefaultsServer
+}
+
+func NewDefaultsServer(config *DefaultsConfig, options *DefaultsOptions) *DefaultsServer {
+	return &DefaultsServer{
+		config:  config,
+		options: options,
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	defaults := map[int]func() interface{}{
+		1: func() interface{} { return &DefaultsOptions{Port: 8080} },
+	}
+
+	container := NewContainer()
+
+	container.RegisterWithDefaults(NewDefaultsServer, defaults)
+	container.Register(func() *DefaultsConfig { return &DefaultsConfig{} })
+
+	if err := container.Validate(&DefaultsApp{}); err != nil {
+		t.Errorf("DefaultsApp could not be validated: %s", err)
+	}
+
+	var app DefaultsApp
+	container.Resolve(&app)
+
+	if app.Server == nil || app.Server.config == nil || app.Server.options == nil || app.Server.options.Port != 8080 {
+		t.Errorf("Missing parameter was not filled by its default")
+	}
+
+	container.Register(func() *DefaultsOptions { return &DefaultsOptions{Port: 9090} })
+
+	var provided DefaultsApp
+	container.Resolve(&provided)
+
+	if provided.Server == nil || provided.Server.options.Port != 9090 {
+		t.Errorf("Default was used although the parameter is provided")
+	}
+
+	container = NewContainer()
+	container.RegisterWithDefaults(NewDefaultsServer, defaults)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Parameter without default was resolved")
+		}
+	}()
+
+	container.Resolve(&DefaultsApp{})
+}
@@ -0,0 +1,161 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RegisterDeferred registers constructors like Register whose values are not
+// constructed by Resolve but only once their type is activated, e.g. for an
+// admin server started on demand. Fields of a root with a deferred type are
+// left empty until the type is activated, other constructors can only depend
+// on a deferred type once it is activated.
+//
+//   container.RegisterDeferred(NewAdminServer)
+//   container.Resolve(&app) // app.Admin is nil
+//
+//   admin, err := container.Activate((*AdminServer)(nil))
+//
+// Dependencies used only by deferred constructors are not constructed until
+// the activation either.
+func (container *Container) RegisterDeferred(constructors ...interface{}) {
+	for _, _constructor := range constructors {
+		details := newConstructor(_constructor)
+		details.Deferred = true
+
+		container.addConstructors(details)
+	}
+}
+
+// Activate constructs the value of the type of the typed nil, including
+// deferred types and their dependencies. Singletons of the container are
+// shared instead of being constructed again, values of resolutions with
+// overrides or seeds are not. A child container that was never resolved
+// shares the singletons of its parent instead. The activated values are
+// singletons of the container themselves, their cleanup functions are run by
+// Dispose.
+//
+// Slices are collected again on every activation, so they include the members
+// registered with the container or its ancestors since the last resolution.
+//
+//   value, err := container.Activate((*AdminServer)(nil))
+//   admin := value.(*AdminServer)
+//
+// An interface is activated with a typed nil of a pointer to it, e.g.
+// (*Handler)(nil). Activating a type again returns the same value, even after
+// later resolutions, until a registration drops its singleton. Activate is
+// safe for concurrent use.
+func (container *Container) Activate(typedNil interface{}) (interface{}, error) {
+	_type := reflect.TypeOf(typedNil)
+
+	if _type == nil {
+		return nil, errors.New("Activate requires a typed nil like (*AdminServer)(nil)")
+	}
+
+	// Interfaces are activated through a typed nil of a pointer to them
+	if _type.Kind() == reflect.Ptr && _type.Elem().Kind() == reflect.Interface {
+		_type = _type.Elem()
+	}
+
+	if err := container.checkDisposed(); err != nil {
+		return nil, err
+	}
+
+	_resolver := container.activation()
+
+	_resolver.Lock.Lock()
+	defer _resolver.Lock.Unlock()
+
+	_resolver.forgetMembers()
+
+	_resolver.Activating = true
+
+	defer func() {
+		_resolver.Activating = false
+	}()
+
+	value, err := _resolver.constructOwned(func() (reflect.Value, error) {
+		return _resolver.resolveType(_type)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !value.IsValid() {
+		return nil, fmt.Errorf("Internal error: activated type '%s' resolved to no value", _type)
+	}
+
+	return value.Interface(), nil
+}
+
+// activation returns the resolver deferred types are activated with. A
+// container without a resolution starts with the singletons of the nearest
+// ancestor that has one.
+func (container *Container) activation() *resolver {
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	container.dropChangedByAncestors()
+
+	if container.activationResolver != nil {
+		return container.activationResolver
+	}
+
+	_resolver := newResolver(context.Background(), container)
+	_resolver.Singletons = true
+
+	if container.parent != nil {
+		parent := container.parent.activation()
+
+		parent.Lock.Lock()
+
+		for constructor, value := range parent.ValueByConstructor {
+			_resolver.ValueByConstructor[constructor] = value
+		}
+
+		parent.Lock.Unlock()
+	}
+
+	container.activationResolver = _resolver
+
+	return _resolver
+}
+
+// forgetMembers drops the values collected for each type so they are
+// collected again with the constructors registered in the meantime. The
+// values of the constructors themselves are kept, only new members are
+// constructed.
+func (_resolver *resolver) forgetMembers() {
+	for _type := range _resolver.ConstructorsByType {
+		if !_resolver.OverriddenTypes[_type] {
+			delete(_resolver.ValuesByType, _type)
+			delete(_resolver.ConstructorsByType, _type)
+		}
+	}
+}
+
+// deferred reports whether the type is only provided by deferred constructors
+// that are not being activated.
+func (_resolver *resolver) deferred(_type reflect.Type) bool {
+	if _resolver.Activating {
+		return false
+	}
+
+	constructors := _resolver.Container.findConstructors(_type)
+
+	for _, constructor := range constructors {
+		if !constructor.Deferred {
+			return false
+		}
+
+		// Values activated before are injected like any other singleton
+		if _, ok := _resolver.constructorInvoked(constructor); ok {
+			return false
+		}
+	}
+
+	return len(constructors) > 0
+}
@@ -0,0 +1,313 @@
+package injector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type DeferredDB struct {
+	id int
+}
+
+type DeferredAdminConfig struct{}
+
+type DeferredAdmin struct {
+	db     *DeferredDB
+	config *DeferredAdminConfig
+}
+
+type DeferredApp struct {
+	DB    *DeferredDB
+	Admin *DeferredAdmin
+}
+
+type DeferredDependentApp struct {
+	Dependent *DeferredDependent
+}
+
+type DeferredDependent struct {
+	admin *DeferredAdmin
+}
+
+func TestDeferred(t *testing.T) {
+	container := NewContainer()
+
+	databases := 0
+	configs := 0
+	stopped := false
+
+	container.Register(func() *DeferredDB {
+		databases++
+		return &DeferredDB{id: databases}
+	})
+
+	container.Register(func() *DeferredAdminConfig {
+		configs++
+		return &DeferredAdminConfig{}
+	})
+
+	container.RegisterDeferred(func(db *DeferredDB, config *DeferredAdminConfig) (*DeferredAdmin, func()) {
+		return &DeferredAdmin{db: db, config: config}, func() { stopped = true }
+	})
+
+	var app DeferredApp
+	container.Resolve(&app)
+
+	if app.DB == nil || app.Admin != nil || configs != 0 {
+		t.Fatalf("Deferred subtree was constructed by Resolve")
+	}
+
+	container.Register(func(admin *DeferredAdmin) *DeferredDependent { return &DeferredDependent{admin: admin} })
+
+	if err := container.ResolveContext(context.Background(), &DeferredDependentApp{}); err == nil {
+		t.Errorf("Constructor depending on a deferred type was resolved")
+	}
+
+	value, err := container.Activate((*DeferredAdmin)(nil))
+
+	if err != nil {
+		t.Fatalf("DeferredAdmin could not be activated: %s", err)
+	}
+
+	admin := value.(*DeferredAdmin)
+
+	if admin.db != app.DB || databases != 1 || configs != 1 {
+		t.Errorf("Shared singletons were not reused by the activation")
+	}
+
+	if again, _ := container.Activate((*DeferredAdmin)(nil)); again != value {
+		t.Errorf("Activating again constructed another value")
+	}
+
+	var dependent DeferredDependentApp
+
+	if err := container.ResolveContext(context.Background(), &dependent); err != nil || dependent.Dependent.admin != admin {
+		t.Errorf("Constructor depending on an activated type did not share its value: %v", err)
+	}
+
+	if err := container.Dispose(); err != nil || !stopped {
+		t.Errorf("Cleanup of the activated value was not run by Dispose")
+	}
+}
+
+type DeferredHandler interface {
+	Path() string
+}
+
+type DeferredPathHandler struct {
+	path string
+	db   *DeferredDB
+}
+
+func (handler *DeferredPathHandler) Path() string {
+	return handler.path
+}
+
+type DeferredAdminServer struct {
+	handlers []DeferredHandler
+}
+
+type DeferredHandlersApp struct {
+	DB       *DeferredDB
+	Handlers []DeferredHandler
+}
+
+func TestDeferredScopeMembers(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *DeferredDB { return &DeferredDB{id: 1} })
+
+	container.RegisterGroup("handlers", func(db *DeferredDB) DeferredHandler {
+		return &DeferredPathHandler{path: "/status", db: db}
+	})
+
+	var app DeferredHandlersApp
+	container.Resolve(&app)
+
+	if len(app.Handlers) != 1 {
+		t.Fatalf("Handlers of the container could not be resolved")
+	}
+
+	child := container.NewChild()
+
+	child.RegisterGroup("handlers", func(db *DeferredDB) DeferredHandler {
+		return &DeferredPathHandler{path: "/admin", db: db}
+	})
+
+	child.RegisterDeferred(func(handlers []DeferredHandler) *DeferredAdminServer {
+		return &DeferredAdminServer{handlers: handlers}
+	})
+
+	value, err := child.Activate((*DeferredAdminServer)(nil))
+
+	if err != nil {
+		t.Fatalf("DeferredAdminServer could not be activated: %s", err)
+	}
+
+	handlers := value.(*DeferredAdminServer).handlers
+
+	if len(handlers) != 2 || handlers[0] != app.Handlers[0] || handlers[1].Path() != "/admin" {
+		t.Fatalf("Handlers of the activated scope were not collected: %v", handlers)
+	}
+
+	if handlers[1].(*DeferredPathHandler).db != app.DB {
+		t.Errorf("Member of the activated scope did not share the singleton of the container")
+	}
+
+	container.RegisterGroup("handlers", func() DeferredHandler {
+		return &DeferredPathHandler{path: "/metrics"}
+	})
+
+	var collected []DeferredHandler
+
+	container.RegisterDeferred(func(handlers []DeferredHandler) *DeferredAdminConfig {
+		collected = handlers
+		return &DeferredAdminConfig{}
+	})
+
+	if _, err := container.Activate((*DeferredAdminConfig)(nil)); err != nil {
+		t.Fatalf("DeferredAdminConfig could not be activated: %s", err)
+	}
+
+	if len(collected) != 2 || collected[0] != app.Handlers[0] || collected[1].Path() != "/metrics" {
+		t.Errorf("Members registered after the resolution were not collected: %v", collected)
+	}
+}
+
+func TestDeferredAcrossResolutions(t *testing.T) {
+	container := NewContainer()
+
+	admins := 0
+
+	container.Register(func() *DeferredDB { return &DeferredDB{id: 1} })
+	container.Register(func() *DeferredAdminConfig { return &DeferredAdminConfig{} })
+	container.RegisterDeferred(func(db *DeferredDB, config *DeferredAdminConfig) *DeferredAdmin {
+		admins++
+		return &DeferredAdmin{db: db, config: config}
+	})
+
+	var app DeferredApp
+	container.Resolve(&app)
+
+	container.ResolveWith(&DeferredApp{}, map[reflect.Type]interface{}{
+		reflect.TypeOf(&DeferredDB{}): &DeferredDB{id: 99},
+	})
+
+	value, err := container.Activate((*DeferredAdmin)(nil))
+
+	if err != nil {
+		t.Fatalf("DeferredAdmin could not be activated: %s", err)
+	}
+
+	admin := value.(*DeferredAdmin)
+
+	if admin.db != app.DB {
+		t.Errorf("Activation received the override of another resolution")
+	}
+
+	var again DeferredApp
+	container.Resolve(&again)
+
+	if activated, _ := container.Activate((*DeferredAdmin)(nil)); activated != value || admins != 1 {
+		t.Errorf("Activating after another resolution constructed %d values", admins)
+	}
+
+	if again.Admin != admin {
+		t.Errorf("Activated value was not injected by a later resolution")
+	}
+}
+
+func TestDeferredAfterRegistration(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *DeferredDB { return &DeferredDB{id: 1} })
+
+	container.RegisterGroup("handlers", func() DeferredHandler {
+		return &DeferredPathHandler{path: "/status"}
+	})
+
+	container.RegisterDeferred(func(handlers []DeferredHandler) *DeferredAdminServer {
+		return &DeferredAdminServer{handlers: handlers}
+	})
+
+	first, err := container.Activate((*DeferredAdminServer)(nil))
+
+	if err != nil {
+		t.Fatalf("DeferredAdminServer could not be activated: %s", err)
+	}
+
+	container.Register(func() *DeferredAdminConfig { return &DeferredAdminConfig{} })
+
+	if again, _ := container.Activate((*DeferredAdminServer)(nil)); again != first {
+		t.Errorf("Unrelated registration dropped the activated server")
+	}
+
+	container.RegisterGroup("handlers", func() DeferredHandler {
+		return &DeferredPathHandler{path: "/metrics"}
+	})
+
+	second, err := container.Activate((*DeferredAdminServer)(nil))
+
+	if err != nil {
+		t.Fatalf("DeferredAdminServer could not be activated again: %s", err)
+	}
+
+	if second == first || len(second.(*DeferredAdminServer).handlers) != 2 {
+		t.Errorf("Activation returned the server constructed before the registration of a handler")
+	}
+}
+
+type DeferredConsole struct {
+	handler DeferredHandler
+	worker  *DeferredDependent
+}
+
+func TestDeferredNested(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterNamed("status", func() DeferredHandler {
+		return &DeferredPathHandler{path: "/status"}
+	})
+
+	container.RegisterDeferred(func(handlers *Registry[DeferredHandler], scope *Scope) (*DeferredConsole, error) {
+		handler, err := handlers.Get("status")
+
+		if err != nil {
+			return nil, err
+		}
+
+		scope.RegisterInstance(&DeferredAdmin{})
+
+		worker := &DeferredDependentApp{}
+		err = scope.Resolve(worker)
+
+		return &DeferredConsole{handler: handler, worker: worker.Dependent}, err
+	})
+
+	container.Register(func(admin *DeferredAdmin) *DeferredDependent {
+		return &DeferredDependent{admin: admin}
+	})
+
+	done := make(chan error, 1)
+
+	go func() {
+		value, err := container.Activate((*DeferredConsole)(nil))
+
+		if err == nil && (value.(*DeferredConsole).handler == nil || value.(*DeferredConsole).worker.admin == nil) {
+			t.Errorf("Console was activated without its nested dependencies")
+		}
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("DeferredConsole could not be activated: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Registry and scope access inside a deferred constructor deadlocked")
+	}
+}
@@ -0,0 +1,60 @@
+package injector
+
+import (
+	"reflect"
+	"sort"
+)
+
+// OrderByDepth configures slices of type []T to be ordered by the depth of the
+// dependencies of their members, members without dependencies first. The
+// depth of a member is one more than the deepest of the values constructed for
+// its parameters, so plugins come after the plugins whose outputs they
+// consume. Members of the same depth keep their registration order.
+//
+//   injector.NewContainer(injector.OrderByDepth[Plugin]())
+//
+// A comparator registered for T takes precedence, members it considers equal
+// stay ordered by depth. An explicit order given by a struct tag takes
+// precedence over both.
+func OrderByDepth[T any]() Option {
+	return func(container *Container) {
+		container.depthOrdered[reflect.TypeOf((*T)(nil)).Elem()] = true
+	}
+}
+
+// require records that the constructor pending last depends on the value of
+// the constructor, which makes it at least one deeper than that value.
+func (_resolver *resolver) require(constructor *constructor) {
+	if len(_resolver.PendingConstructors) == 0 {
+		return
+	}
+
+	dependent := _resolver.PendingConstructors[len(_resolver.PendingConstructors)-1]
+	_resolver.Requirements[dependent] = append(_resolver.Requirements[dependent], constructor)
+
+	if pending := &_resolver.PendingDepths[len(_resolver.PendingDepths)-1]; *pending < _resolver.Depth[constructor]+1 {
+		*pending = _resolver.Depth[constructor] + 1
+	}
+}
+
+// sortByDepth sorts the values of the constructors stably by the depth the
+// resolver recorded for their constructors.
+func (_resolver *resolver) sortByDepth(constructors []*constructor, values []reflect.Value) {
+	indices := make([]int, len(values))
+
+	for i := range indices {
+		indices[i] = i
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		return _resolver.Depth[constructors[indices[i]]] < _resolver.Depth[constructors[indices[j]]]
+	})
+
+	sorted := make([]reflect.Value, len(values))
+
+	for i, index := range indices {
+		sorted[i] = values[index]
+	}
+
+	copy(values, sorted)
+}
@@ -0,0 +1,71 @@
+package injector
+
+import (
+	"reflect"
+	"testing"
+)
+
+type DepthPlugin interface {
+	Name() string
+}
+
+type DepthNamedPlugin struct {
+	name string
+}
+
+func (plugin *DepthNamedPlugin) Name() string {
+	return plugin.name
+}
+
+type DepthStore struct{}
+
+type DepthCache struct {
+	store *DepthStore
+}
+
+type DepthApp struct {
+	Plugins []DepthPlugin
+}
+
+func TestOrderByDepth(t *testing.T) {
+	register := func(container *Container) {
+		container.Register(
+			func(*DepthCache) DepthPlugin { return &DepthNamedPlugin{name: "audit"} },
+			func(*DepthStore) DepthPlugin { return &DepthNamedPlugin{name: "cache"} },
+			func() DepthPlugin { return &DepthNamedPlugin{name: "store"} },
+			func() DepthPlugin { return &DepthNamedPlugin{name: "metrics"} },
+			func(store *DepthStore) *DepthCache { return &DepthCache{store: store} },
+			func() *DepthStore { return &DepthStore{} },
+		)
+	}
+
+	names := func(app DepthApp) []string {
+		var names []string
+
+		for _, plugin := range app.Plugins {
+			names = append(names, plugin.Name())
+		}
+
+		return names
+	}
+
+	container := NewContainer(OrderByDepth[DepthPlugin]())
+	register(container)
+
+	var app DepthApp
+	container.Resolve(&app)
+
+	if expected := []string{"store", "metrics", "cache", "audit"}; !reflect.DeepEqual(names(app), expected) {
+		t.Errorf("Plugins were not ordered by depth: %v", names(app))
+	}
+
+	container = NewContainer()
+	register(container)
+
+	var unordered DepthApp
+	container.Resolve(&unordered)
+
+	if expected := []string{"audit", "cache", "store", "metrics"}; !reflect.DeepEqual(names(unordered), expected) {
+		t.Errorf("Plugins were not in registration order: %v", names(unordered))
+	}
+}
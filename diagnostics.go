@@ -0,0 +1,171 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DiagnosticKind identifies the kind of a diagnostic.
+type DiagnosticKind int
+
+const (
+	// SharedInstance is reported when distinct constructors return the very
+	// same instance, e.g. a package-level singleton.
+	SharedInstance DiagnosticKind = iota
+
+	// SingleImplementation is reported when a slice of an interface is
+	// filled with a single value, so the single form may have been meant.
+	SingleImplementation
+
+	// OrphanedGroupMember is reported by Validate when a constructor is a
+	// member of a group that is never injected.
+	OrphanedGroupMember
+
+	// EmptySlice is reported by Validate when a slice of an interface is
+	// injected although no constructor implements the interface.
+	EmptySlice
+
+	// EmptyRoot is reported when a root without fields is resolved, which
+	// usually means the wrong struct was passed.
+	EmptyRoot
+
+	// AmbiguousInterface is reported by Validate when a constructor takes a
+	// single value of an interface with several implementations and neither
+	// names, priorities nor bindings select one of them.
+	AmbiguousInterface
+
+	// UnexportedImplementation is reported by Validate with strict exports
+	// when a constructor returns an unexported concrete type that is only
+	// injected through the interfaces it implements.
+	UnexportedImplementation
+)
+
+// Diagnostic describes a suspicious but non-fatal finding about the object
+// graph.
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Type    reflect.Type
+	Message string
+}
+
+// WithDiagnostics registers a handler that is called for every diagnostic
+// found while resolving.
+func WithDiagnostics(handler func(Diagnostic)) Option {
+	return func(container *Container) {
+		container.diagnosticHandler = handler
+	}
+}
+
+// WithVerboseErrors extends resolution errors with the types constructed
+// before the failure to show how far the wiring got.
+func WithVerboseErrors() Option {
+	return func(container *Container) {
+		container.verboseErrors = true
+	}
+}
+
+// WithStrictExports lets Validate report constructors returning an unexported
+// concrete type that is only injected through interfaces, since consumers of
+// other packages cannot refer to the concrete type. Return the interface or
+// export the type instead.
+//
+//   func NewStore() *memoryStore {…} // Reported if only injected as Store
+func WithStrictExports() Option {
+	return func(container *Container) {
+		container.strictExports = true
+	}
+}
+
+// explain extends a resolution error with the constructed types if verbose
+// errors are enabled.
+func (container *Container) explain(err error, _resolver *resolver) error {
+	if !container.verboseErrors {
+		return err
+	}
+
+	if len(_resolver.InvokedConstructors) == 0 {
+		return fmt.Errorf("%w; nothing was constructed before the failure", err)
+	}
+
+	var types []string
+
+	for _, constructor := range _resolver.InvokedConstructors {
+		types = append(types, fmt.Sprintf("'%s'", constructor.ReturnType))
+	}
+
+	return fmt.Errorf("%w; constructed before the failure: %s", err, strings.Join(types, ", "))
+}
+
+func (container *Container) report(kind DiagnosticKind, _type reflect.Type, format string, args ...interface{}) {
+	if container.diagnosticHandler == nil {
+		return
+	}
+
+	container.diagnosticHandler(Diagnostic{
+		Kind:    kind,
+		Type:    _type,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// reportSingleImplementation reports a slice type that received a single
+// value.
+func (container *Container) reportSingleImplementation(sliceType reflect.Type) {
+	container.report(
+		SingleImplementation, sliceType,
+		"Slice of type '%s' has a single implementation, inject '%s' instead",
+		sliceType, sliceType.Elem(),
+	)
+}
+
+// reportSharedInstances reports every pair of constructors whose values
+// point to the same instance.
+func (container *Container) reportSharedInstances(valueByConstructor map[*constructor]reflect.Value) {
+	if container.diagnosticHandler == nil {
+		return
+	}
+
+	constructorByKey := make(map[interface{}]*constructor)
+
+	// Walk constructors in registration order to report deterministically
+	for _, constructor := range container.allConstructors() {
+		value, ok := valueByConstructor[constructor]
+
+		// Decorators may legitimately return the value they decorate
+		if !ok || constructor.Decorator {
+			continue
+		}
+
+		key := identityKey(value)
+
+		if key == nil {
+			continue
+		}
+
+		if other, ok := constructorByKey[key]; ok {
+			_type := key.(instanceKey).Type
+
+			container.report(
+				SharedInstance, _type,
+				"Constructors '%s' and '%s' return the same instance of type '%s'",
+				other.Function.Type(), constructor.Function.Type(), _type,
+			)
+
+			continue
+		}
+
+		constructorByKey[key] = constructor
+	}
+}
+
+// reportEmptyRoots reports the roots that have no fields to inject.
+func (container *Container) reportEmptyRoots(roots []interface{}) {
+	for _, root := range roots {
+		rootType := reflect.TypeOf(root)
+
+		if rootType.Kind() == reflect.Ptr && rootType.Elem().Kind() == reflect.Struct && rootType.Elem().NumField() == 0 {
+			container.report(EmptyRoot, rootType, "Root of type '%s' has no fields to inject", rootType)
+		}
+	}
+}
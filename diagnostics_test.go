@@ -0,0 +1,272 @@
+package injector
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type SharedApp struct {
+	Foo *SharedFoo
+}
+
+type SharedFoo struct {
+	loggers []SharedLoggerInterface
+}
+
+type SharedLoggerInterface interface {
+	Log(string)
+}
+
+type SharedLogger struct {
+	prefix string
+}
+
+func (logger *SharedLogger) Log(string) {
+}
+
+var sharedLogger = &SharedLogger{}
+
+func NewSharedFoo(loggers []SharedLoggerInterface) *SharedFoo {
+	return &SharedFoo{
+		loggers: loggers,
+	}
+}
+
+func NewSharedLogger() *SharedLogger {
+	return sharedLogger
+}
+
+func NewSharedLoggerInterface() SharedLoggerInterface {
+	return sharedLogger
+}
+
+func TestSharedInstance(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	container.Register(NewSharedFoo, NewSharedLogger, NewSharedLoggerInterface)
+
+	app := &SharedApp{}
+	container.Resolve(app)
+
+	if len(diagnostics) != 1 || diagnostics[0].Kind != SharedInstance {
+		t.Errorf("Shared instance was not detected")
+	}
+}
+
+func TestSharedInstanceNotReported(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	container.Register(NewSharedFoo, func() *SharedLogger {
+		return &SharedLogger{}
+	}, func() SharedLoggerInterface {
+		return &SharedLogger{}
+	})
+
+	app := &SharedApp{}
+	container.Resolve(app)
+
+	if len(diagnostics) != 0 {
+		t.Errorf("Unexpected diagnostic %v", diagnostics)
+	}
+}
+
+func TestSingleImplementation(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	container.Register(NewSharedFoo, func() *SharedLogger {
+		return &SharedLogger{}
+	})
+
+	app := &SharedApp{}
+	container.Resolve(app)
+
+	if len(app.Foo.loggers) != 1 {
+		t.Errorf("Single implementation was not injected")
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Kind != SingleImplementation {
+		t.Errorf("Single implementation was not reported")
+	}
+}
+
+type SingleImplementationApp struct {
+	Loggers []SharedLoggerInterface
+}
+
+func TestSingleImplementationInstances(t *testing.T) {
+	registrations := map[string]func(*Container){
+		"instances": func(container *Container) {
+			container.RegisterInstance(&SharedLogger{})
+		},
+		"constructors": func(container *Container) {
+			container.Register(func() *SharedLogger {
+				return &SharedLogger{}
+			})
+		},
+	}
+
+	for name, register := range registrations {
+		t.Run(name, func(t *testing.T) {
+			var diagnostics []Diagnostic
+
+			container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+				diagnostics = append(diagnostics, diagnostic)
+			}))
+
+			register(container)
+
+			app := &SingleImplementationApp{}
+			container.Resolve(app)
+
+			if len(app.Loggers) != 1 {
+				t.Errorf("Single implementation was not injected")
+			}
+
+			if len(diagnostics) != 1 || diagnostics[0].Kind != SingleImplementation {
+				t.Errorf("Single implementation was not reported: %v", diagnostics)
+			}
+		})
+	}
+}
+
+type VerboseApp struct {
+	Service *VerboseService
+}
+
+type VerboseService struct {
+	config *VerboseConfig
+	store  VerboseStore
+}
+
+type VerboseConfig struct {
+	path string
+}
+
+type VerboseStore interface {
+	Load() string
+}
+
+func NewVerboseService(config *VerboseConfig, store VerboseStore) *VerboseService {
+	return &VerboseService{
+		config: config,
+		store:  store,
+	}
+}
+
+func NewVerboseConfig() *VerboseConfig {
+	return &VerboseConfig{}
+}
+
+type EmptyRootApp struct {
+}
+
+func TestEmptyRoot(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	container.Resolve(&EmptyRootApp{})
+
+	if len(diagnostics) != 1 || diagnostics[0].Kind != EmptyRoot || diagnostics[0].Type != reflect.TypeOf(&EmptyRootApp{}) {
+		t.Errorf("Empty root was not reported: %v", diagnostics)
+	}
+}
+
+func TestVerboseErrors(t *testing.T) {
+	container := NewContainer(WithVerboseErrors())
+
+	container.Register(NewVerboseService, NewVerboseConfig)
+
+	err := container.ResolveContext(context.Background(), &VerboseApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "constructed before the failure: '*injector.VerboseConfig'") {
+		t.Errorf("Constructed types were not listed: %v", err)
+	}
+
+	container = NewContainer()
+
+	container.Register(NewVerboseService, NewVerboseConfig)
+
+	err = container.ResolveContext(context.Background(), &VerboseApp{})
+
+	if err == nil || strings.Contains(err.Error(), "constructed before") {
+		t.Errorf("Error was verbose by default: %v", err)
+	}
+}
+
+type StrictExportsStore interface {
+	Load() string
+}
+
+type strictExportsMemoryStore struct{}
+
+func (*strictExportsMemoryStore) Load() string {
+	return "memory"
+}
+
+type strictExportsCache struct{}
+
+func (*strictExportsCache) Load() string {
+	return "cache"
+}
+
+type StrictExportsApp struct {
+	Store StrictExportsStore `inject:"type=*injector.strictExportsMemoryStore"`
+	Cache *strictExportsCache
+}
+
+func TestStrictExports(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	register := func(container *Container) {
+		container.Register(
+			func() *strictExportsMemoryStore { return &strictExportsMemoryStore{} },
+			func() *strictExportsCache { return &strictExportsCache{} },
+		)
+	}
+
+	container := NewContainer(WithStrictExports(), WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	register(container)
+
+	if err := container.Validate(&StrictExportsApp{}); err != nil {
+		t.Fatalf("StrictExportsApp could not be validated: %s", err)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Kind != UnexportedImplementation || diagnostics[0].Type != reflect.TypeOf(&strictExportsMemoryStore{}) {
+		t.Errorf("Unexported implementation was not reported: %v", diagnostics)
+	}
+
+	diagnostics = nil
+
+	container = NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		diagnostics = append(diagnostics, diagnostic)
+	}))
+
+	register(container)
+	container.Validate(&StrictExportsApp{})
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Kind == UnexportedImplementation {
+			t.Errorf("Unexported implementation was reported without strict exports")
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Diff describes how the registrations of two containers differ, e.g. to
+// verify that a refactored wiring provides the same types as the original.
+// Types are listed in the order of their names.
+type Diff struct {
+	// OnlyInA holds the types only provided by the first container
+	OnlyInA []reflect.Type
+	// OnlyInB holds the types only provided by the second container
+	OnlyInB []reflect.Type
+	// Changed holds the types provided by both containers through different
+	// providers
+	Changed []ProviderChange
+}
+
+// ProviderChange describes the providers of a type in both containers of a
+// Diff.
+type ProviderChange struct {
+	Type reflect.Type
+	A    []string
+	B    []string
+}
+
+// DiffContainers compares the registrations visible to both containers. A
+// provider is identified by the name of its constructor function along with
+// the name, priority and variant it was registered with and whether it is
+// transient, deferred, a decorator or keyed. No constructor is invoked.
+//
+//   if diff := injector.DiffContainers(legacy, refactored); !diff.Empty() {
+//     t.Errorf("Wiring differs:\n%s", diff)
+//   }
+func DiffContainers(a, b *Container) Diff {
+	providersA := a.providersByType()
+	providersB := b.providersByType()
+
+	var diff Diff
+
+	for _, _type := range sortedTypes(providersA) {
+		providers, ok := providersB[_type]
+
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, _type)
+			continue
+		}
+
+		if !reflect.DeepEqual(providersA[_type], providers) {
+			diff.Changed = append(diff.Changed, ProviderChange{
+				Type: _type,
+				A:    providersA[_type],
+				B:    providers,
+			})
+		}
+	}
+
+	for _, _type := range sortedTypes(providersB) {
+		if _, ok := providersA[_type]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, _type)
+		}
+	}
+
+	return diff
+}
+
+// Empty reports whether both containers provide the same types through the
+// same providers.
+func (diff Diff) Empty() bool {
+	return len(diff.OnlyInA) == 0 && len(diff.OnlyInB) == 0 && len(diff.Changed) == 0
+}
+
+// String lists the differences one per line, types only provided by the first
+// container prefixed with "-", types only provided by the second with "+" and
+// types with different providers with "~".
+func (diff Diff) String() string {
+	var lines []string
+
+	for _, _type := range diff.OnlyInA {
+		lines = append(lines, fmt.Sprintf("- %s", _type))
+	}
+
+	for _, _type := range diff.OnlyInB {
+		lines = append(lines, fmt.Sprintf("+ %s", _type))
+	}
+
+	for _, change := range diff.Changed {
+		lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", change.Type, strings.Join(change.A, ", "), strings.Join(change.B, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// providersByType returns the sorted descriptions of the providers of each
+// type registered with the container or one of its ancestors, along with the
+// keyed constructors of the container.
+func (container *Container) providersByType() map[reflect.Type][]string {
+	providers := make(map[reflect.Type][]string)
+
+	for _, constructor := range container.allConstructors() {
+		providers[constructor.ReturnType] = append(providers[constructor.ReturnType], constructor.describe())
+	}
+
+	for _, keyed := range container.keyedConstructors {
+		returnType := keyed.Constructor.ReturnType
+		providers[returnType] = append(providers[returnType], fmt.Sprintf("%s (keyed by %s)", keyed.Constructor.describe(), keyed.KeyType))
+	}
+
+	for _, descriptions := range providers {
+		sort.Strings(descriptions)
+	}
+
+	return providers
+}
+
+// describe identifies the constructor by the name of its function. Instances
+// and constructors created at runtime are identified by their signature.
+func (constructor *constructor) describe() string {
+	var description string
+
+	switch name := runtime.FuncForPC(constructor.Function.Pointer()).Name(); {
+	case constructor.Instance.IsValid():
+		description = fmt.Sprintf("instance of '%s'", constructor.ReturnType)
+	case name == "" || strings.HasPrefix(name, "reflect."):
+		description = constructor.Function.Type().String()
+	default:
+		description = name
+	}
+
+	if constructor.Name != "" {
+		description += fmt.Sprintf(" (name %s)", constructor.Name)
+	}
+
+	if constructor.Priority != 0 {
+		description += fmt.Sprintf(" (priority %d)", constructor.Priority)
+	}
+
+	if constructor.Variant != nil {
+		description += fmt.Sprintf(" (variant %v)", constructor.Variant)
+	}
+
+	if constructor.Transient {
+		description += " (transient)"
+	}
+
+	if constructor.Deferred {
+		description += " (deferred)"
+	}
+
+	if constructor.Decorator {
+		description += " (decorator)"
+	}
+
+	return description
+}
+
+// sortedTypes returns the keys of the providers ordered by their names.
+func sortedTypes(providers map[reflect.Type][]string) []reflect.Type {
+	var types []reflect.Type
+
+	for _type := range providers {
+		types = append(types, _type)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	return types
+}
@@ -0,0 +1,130 @@
+package injector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type DiffConfig struct{}
+
+type DiffStore interface {
+	Load() string
+}
+
+type DiffMemoryStore struct{}
+
+func (*DiffMemoryStore) Load() string {
+	return "memory"
+}
+
+type DiffCache struct{}
+
+type DiffMetrics struct{}
+
+func NewDiffConfig() *DiffConfig {
+	return &DiffConfig{}
+}
+
+func NewDiffStore(*DiffConfig) DiffStore {
+	return &DiffMemoryStore{}
+}
+
+func NewDiffFakeStore() DiffStore {
+	return &DiffMemoryStore{}
+}
+
+func TestDiffContainers(t *testing.T) {
+	a := NewContainer()
+	a.Register(NewDiffConfig, NewDiffStore, func() *DiffCache { return &DiffCache{} })
+
+	b := NewContainer()
+	b.Register(NewDiffConfig, NewDiffFakeStore, func() *DiffMetrics { return &DiffMetrics{} })
+
+	diff := DiffContainers(a, b)
+
+	if !reflect.DeepEqual(diff.OnlyInA, []reflect.Type{reflect.TypeOf(&DiffCache{})}) {
+		t.Errorf("Types only provided by a were %v", diff.OnlyInA)
+	}
+
+	if !reflect.DeepEqual(diff.OnlyInB, []reflect.Type{reflect.TypeOf(&DiffMetrics{})}) {
+		t.Errorf("Types only provided by b were %v", diff.OnlyInB)
+	}
+
+	storeType := reflect.TypeOf((*DiffStore)(nil)).Elem()
+
+	if len(diff.Changed) != 1 || diff.Changed[0].Type != storeType {
+		t.Fatalf("Divergent provider was not reported: %v", diff.Changed)
+	}
+
+	change := diff.Changed[0]
+
+	if len(change.A) != 1 || !strings.HasSuffix(change.A[0], ".NewDiffStore") || len(change.B) != 1 || !strings.HasSuffix(change.B[0], ".NewDiffFakeStore") {
+		t.Errorf("Providers of the divergent type were %v and %v", change.A, change.B)
+	}
+
+	lines := strings.Split(diff.String(), "\n")
+
+	if len(lines) != 3 || lines[0] != "- *injector.DiffCache" || lines[1] != "+ *injector.DiffMetrics" || !strings.HasPrefix(lines[2], "~ injector.DiffStore: ") {
+		t.Errorf("Diff was printed as %q", diff.String())
+	}
+
+	if !DiffContainers(a, a).Empty() {
+		t.Errorf("Container differs from itself")
+	}
+}
+
+type DiffShardID int
+
+func NewDiffKeyedCache(DiffShardID) *DiffCache {
+	return &DiffCache{}
+}
+
+func NewDiffCache() *DiffCache {
+	return &DiffCache{}
+}
+
+func TestDiffAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(*Container)
+		expected string
+	}{
+		{
+			name:     "priority",
+			register: func(container *Container) { container.RegisterWithPriority(10, NewDiffCache) },
+			expected: ".NewDiffCache (priority 10)",
+		},
+		{
+			name:     "transient",
+			register: func(container *Container) { container.RegisterTransient(NewDiffCache) },
+			expected: ".NewDiffCache (transient)",
+		},
+		{
+			name:     "variant",
+			register: func(container *Container) { container.RegisterVariant("testing", NewDiffCache) },
+			expected: ".NewDiffCache (variant testing)",
+		},
+		{
+			name:     "keyed",
+			register: func(container *Container) { container.RegisterKeyed(NewDiffKeyedCache) },
+			expected: ".NewDiffKeyedCache (keyed by injector.DiffShardID)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewContainer()
+			a.Register(NewDiffCache)
+
+			b := NewContainer()
+			test.register(b)
+
+			diff := DiffContainers(a, b)
+
+			if len(diff.Changed) != 1 || len(diff.Changed[0].B) != 1 || !strings.HasSuffix(diff.Changed[0].B[0], test.expected) {
+				t.Errorf("Registration differing in its %s was reported as %q", test.name, diff)
+			}
+		})
+	}
+}
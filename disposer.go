@@ -0,0 +1,249 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+var cleanupType = reflect.TypeOf((func())(nil))
+var cleanupErrorType = reflect.TypeOf((func() error)(nil))
+
+// ErrDisposed is returned when a container is used after it was disposed.
+var ErrDisposed = errors.New("Container has been disposed")
+
+// Disposer tears down the object graph of a single resolution.
+type Disposer interface {
+	// Dispose runs the cleanup functions returned by the constructors in
+	// reverse order of construction. All cleanup functions are run even if
+	// some of them fail, their errors are joined. Calling it again has no
+	// effect and returns nil.
+	Dispose() error
+}
+
+type disposer struct {
+	once     sync.Once
+	cleanups []Cleanup
+}
+
+func (_disposer *disposer) Dispose() error {
+	var err error
+
+	_disposer.once.Do(func() {
+		err = runCleanups(_disposer.cleanups)
+	})
+
+	return err
+}
+
+// ResolveWithDisposer works like ResolveContext but also returns a Disposer
+// for the constructed graph. If the resolution fails, the cleanup functions of
+// the constructors invoked so far are run before the error is returned. The
+// graph is constructed from scratch, it neither reuses nor adds to the
+// singletons of the container since the Disposer tears it down.
+//
+//   disposer, err := container.ResolveWithDisposer(&app)
+//   …
+//   defer disposer.Dispose()
+func (container *Container) ResolveWithDisposer(root interface{}) (Disposer, error) {
+	cleanups, err := container.resolve(context.Background(), []interface{}{root}, isolate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &disposer{cleanups: cleanups}, nil
+}
+
+// Dispose runs the cleanup functions of all resolutions of the container
+// that are not owned by a Disposer in reverse order of construction and
+// closes the container. Resolutions and lookups of a closed container and its
+// children fail with ErrDisposed. Calling it again has no effect and returns
+// nil.
+func (container *Container) Dispose() error {
+	container.lock.Lock()
+
+	if container.disposed {
+		container.lock.Unlock()
+		return nil
+	}
+
+	cleanups := container.cleanups
+
+	container.disposed = true
+	container.cleanups = nil
+
+	container.lock.Unlock()
+
+	return runCleanups(cleanups)
+}
+
+// checkDisposed returns ErrDisposed if the container or one of its ancestors
+// has been disposed.
+func (container *Container) checkDisposed() error {
+	for current := container; current != nil; current = current.parent {
+		current.lock.Lock()
+		disposed := current.disposed
+		current.lock.Unlock()
+
+		if disposed {
+			return ErrDisposed
+		}
+	}
+
+	return nil
+}
+
+// resolveOwned resolves the roots and keeps the cleanup functions until the
+// container is disposed. If the container was disposed in the meantime, the
+// cleanup functions are run right away.
+func (container *Container) resolveOwned(ctx context.Context, roots []interface{}, prepare func(*resolver) error) error {
+	cleanups, err := container.resolve(ctx, roots, prepare)
+
+	if err != nil {
+		return err
+	}
+
+	container.lock.Lock()
+
+	if container.disposed {
+		container.lock.Unlock()
+		return errors.Join(ErrDisposed, runCleanups(cleanups))
+	}
+
+	container.cleanups = append(container.cleanups, cleanups...)
+	container.lock.Unlock()
+
+	return nil
+}
+
+// constructOwned constructs values after the resolution of the resolver has
+// finished, e.g. for Lazy.Get. The resolution handed out its cleanup functions
+// already, so the cleanup functions of the constructed values are kept by the
+// container until it is disposed. They are run right away if the construction
+// fails or the container was disposed in the meantime. Values constructed by
+// a constructor that is still being invoked belong to its resolution.
+func (_resolver *resolver) constructOwned(construct func() (reflect.Value, error)) (reflect.Value, error) {
+	if len(_resolver.PendingConstructors) > 0 {
+		return construct()
+	}
+
+	constructed := len(_resolver.Cleanups)
+	invoked := len(_resolver.InvokedConstructors)
+
+	value, err := construct()
+
+	cleanups := append([]Cleanup(nil), _resolver.Cleanups[constructed:]...)
+	_resolver.Cleanups = _resolver.Cleanups[:constructed]
+
+	if err != nil {
+		if cleanupErr := runCleanups(cleanups); cleanupErr != nil {
+			return reflect.Value{}, errors.Join(err, cleanupErr)
+		}
+
+		return reflect.Value{}, err
+	}
+
+	container := _resolver.Container
+
+	container.lock.Lock()
+
+	if container.disposed {
+		container.lock.Unlock()
+		return reflect.Value{}, errors.Join(ErrDisposed, runCleanups(cleanups))
+	}
+
+	container.cleanups = append(container.cleanups, cleanups...)
+	container.lock.Unlock()
+
+	// The values are owned by the container now like its other singletons
+	if _resolver.Singletons {
+		container.cacheSingletons(_resolver, _resolver.InvokedConstructors[invoked:])
+	}
+
+	return value, nil
+}
+
+// abort tears down whatever was constructed before the resolution failed and
+// returns the resolution error along with the errors of the cleanups.
+func (container *Container) abort(err error, _resolver *resolver) error {
+	err = container.explain(err, _resolver)
+
+	if cleanupErr := runCleanups(_resolver.Cleanups); cleanupErr != nil {
+		return errors.Join(err, cleanupErr)
+	}
+
+	return err
+}
+
+// Cleanup is a cleanup function returned by the constructor of a type.
+type Cleanup struct {
+	// Type is the return type of the constructor
+	Type reflect.Type
+	// Run runs the cleanup function. Only the first call has an effect, later
+	// calls return nil, so a cleanup run by the application is not run again
+	// by Dispose.
+	Run func() error
+}
+
+// Cleanups returns the cleanup functions of all resolutions of the container
+// that are not owned by a Disposer in construction order, e.g. to drain
+// connections before the database is closed. Dispose runs them in reverse
+// order, except for those already run by the application.
+//
+//   for _, cleanup := range container.Cleanups() {
+//     if cleanup.Type == reflect.TypeOf(&Server{}) {
+//       cleanup.Run()
+//     }
+//   }
+//
+//   container.Dispose()
+func (container *Container) Cleanups() []Cleanup {
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	return append([]Cleanup(nil), container.cleanups...)
+}
+
+// newCleanup wraps a cleanup function returned by the constructor of the
+// type.
+func newCleanup(_type reflect.Type, cleanup interface{}) Cleanup {
+	var once sync.Once
+
+	run, ok := cleanup.(func() error)
+
+	if !ok {
+		run = func() error {
+			cleanup.(func())()
+			return nil
+		}
+	}
+
+	return Cleanup{
+		Type: _type,
+		Run: func() error {
+			var err error
+
+			once.Do(func() {
+				err = run()
+			})
+
+			return err
+		},
+	}
+}
+
+// runCleanups runs the cleanup functions in reverse order and joins their
+// errors.
+func runCleanups(cleanups []Cleanup) error {
+	var errs []error
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := cleanups[i].Run(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,307 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type DisposeApp struct {
+	Bar *DisposeBar
+}
+
+type DisposeFoo struct {
+	value int
+}
+
+type DisposeBar struct {
+	foo *DisposeFoo
+}
+
+type DisposeBroken interface {
+	Broken()
+}
+
+type DisposeBrokenApp struct {
+	Bar    *DisposeBar
+	Broken DisposeBroken
+}
+
+func TestDispose(t *testing.T) {
+	container := NewContainer()
+
+	var disposed []string
+
+	container.Register(func(foo *DisposeFoo) (*DisposeBar, func()) {
+		return &DisposeBar{foo: foo}, func() { disposed = append(disposed, "bar") }
+	})
+
+	container.Register(func() (*DisposeFoo, func()) {
+		return &DisposeFoo{}, func() { disposed = append(disposed, "foo") }
+	})
+
+	app := DisposeApp{}
+
+	disposer, err := container.ResolveWithDisposer(&app)
+
+	if err != nil {
+		t.Fatalf("App could not be resolved: %s", err)
+	}
+
+	if app.Bar == nil || app.Bar.foo == nil {
+		t.Errorf("Bar could not be resolved")
+	}
+
+	if len(disposed) != 0 {
+		t.Errorf("Cleanup ran before Dispose")
+	}
+
+	disposer.Dispose()
+	disposer.Dispose()
+
+	if len(disposed) != 2 || disposed[0] != "bar" || disposed[1] != "foo" {
+		t.Errorf("Unexpected disposal order %v", disposed)
+	}
+}
+
+func TestDisposeFailedResolution(t *testing.T) {
+	container := NewContainer()
+
+	var disposed []string
+
+	container.Register(func() (*DisposeFoo, func()) {
+		return &DisposeFoo{}, func() { disposed = append(disposed, "foo") }
+	})
+
+	container.Register(func(foo *DisposeFoo) *DisposeBar {
+		return &DisposeBar{foo: foo}
+	})
+
+	if _, err := container.ResolveWithDisposer(&DisposeBrokenApp{}); err == nil {
+		t.Errorf("Missing dependency was not reported")
+	}
+
+	if len(disposed) != 1 {
+		t.Errorf("Constructed Foo was not cleaned up")
+	}
+}
+
+func TestDisposeCanceled(t *testing.T) {
+	container := NewContainer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	disposed := false
+
+	container.Register(func() (*DisposeFoo, func()) {
+		cancel()
+		return &DisposeFoo{}, func() { disposed = true }
+	})
+
+	container.Register(func(foo *DisposeFoo) *DisposeBar {
+		return &DisposeBar{foo: foo}
+	})
+
+	if err := container.ResolveContext(ctx, &DisposeApp{}); err == nil {
+		t.Errorf("Cancellation was not reported")
+	}
+
+	if !disposed {
+		t.Errorf("Constructed Foo was not cleaned up")
+	}
+}
+
+type DisposeBaz struct {
+	bar *DisposeBar
+}
+
+func TestDisposeErrors(t *testing.T) {
+	container := NewContainer()
+
+	errFoo := errors.New("foo")
+	errBaz := errors.New("baz")
+
+	barDisposed := false
+
+	container.Register(func() (*DisposeFoo, func() error) {
+		return &DisposeFoo{}, func() error { return errFoo }
+	})
+
+	container.Register(func(foo *DisposeFoo) (*DisposeBar, func()) {
+		return &DisposeBar{foo: foo}, func() { barDisposed = true }
+	})
+
+	container.Register(func(bar *DisposeBar) (*DisposeBaz, func() error) {
+		return &DisposeBaz{bar: bar}, func() error { return errBaz }
+	})
+
+	app := &struct {
+		Baz *DisposeBaz
+	}{}
+
+	disposer, err := container.ResolveWithDisposer(app)
+
+	if err != nil {
+		t.Fatalf("App could not be resolved: %s", err)
+	}
+
+	err = disposer.Dispose()
+
+	if !errors.Is(err, errFoo) || !errors.Is(err, errBaz) {
+		t.Errorf("Cleanup errors were not reported: %v", err)
+	}
+
+	if !barDisposed {
+		t.Errorf("Cleanup was skipped after an error")
+	}
+
+	if err := disposer.Dispose(); err != nil {
+		t.Errorf("Second Dispose returned an error: %s", err)
+	}
+}
+
+type DisposeLookupApp struct {
+	Foo *Lazy[*DisposeFoo]
+}
+
+func TestDisposeContainer(t *testing.T) {
+	container := NewContainer()
+
+	disposed := 0
+
+	container.Register(func() (*DisposeFoo, func()) {
+		return &DisposeFoo{}, func() { disposed++ }
+	})
+
+	container.Register(func(foo *DisposeFoo) *DisposeBar {
+		return &DisposeBar{foo: foo}
+	})
+
+	app := &DisposeApp{}
+	container.Resolve(app)
+
+	lookup := &DisposeLookupApp{}
+	container.Resolve(lookup)
+
+	if err := container.Dispose(); err != nil {
+		t.Fatalf("Container could not be disposed: %s", err)
+	}
+
+	if disposed != 1 {
+		t.Errorf("Cleanup of resolution ran %d times", disposed)
+	}
+
+	if err := container.ResolveContext(context.Background(), &DisposeApp{}); err != ErrDisposed {
+		t.Errorf("Resolve after Dispose returned %v", err)
+	}
+
+	if _, err := lookup.Foo.Get(); err != ErrDisposed {
+		t.Errorf("Get after Dispose returned %v", err)
+	}
+
+	if err := container.NewChild().ResolveContext(context.Background(), &DisposeApp{}); err != ErrDisposed {
+		t.Errorf("Resolve of child after Dispose returned %v", err)
+	}
+
+	if err := container.Dispose(); err != nil || disposed != 1 {
+		t.Errorf("Second Dispose was not a no-op")
+	}
+}
+
+type DisposeConn struct {
+	closed bool
+}
+
+func (conn *DisposeConn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+type DisposePool struct {
+	closed bool
+}
+
+func (pool *DisposePool) Close() error {
+	pool.closed = true
+	return nil
+}
+
+type DisposeOwnershipApp struct {
+	Conn *DisposeConn
+	Pool *DisposePool
+}
+
+func TestDisposeInstance(t *testing.T) {
+	container := NewContainer()
+
+	conn := &DisposeConn{}
+	container.RegisterInstance(conn)
+
+	container.Register(func(*DisposeConn) (*DisposePool, func() error) {
+		pool := &DisposePool{}
+		return pool, pool.Close
+	})
+
+	var app DisposeOwnershipApp
+	container.Resolve(&app)
+
+	if err := container.Dispose(); err != nil {
+		t.Fatalf("Container could not be disposed: %s", err)
+	}
+
+	if !app.Pool.closed {
+		t.Errorf("Constructed pool was not closed")
+	}
+
+	if conn.closed {
+		t.Errorf("Instance was closed although it is owned by the caller")
+	}
+}
+
+type DisposeSequenceDB struct{}
+
+type DisposeSequenceServer struct {
+	db *DisposeSequenceDB
+}
+
+type DisposeSequenceApp struct {
+	Server *DisposeSequenceServer
+}
+
+func TestDisposeCleanups(t *testing.T) {
+	container := NewContainer()
+
+	var closed []string
+
+	container.Register(func() (*DisposeSequenceDB, func()) {
+		return &DisposeSequenceDB{}, func() { closed = append(closed, "db") }
+	})
+
+	container.Register(func(db *DisposeSequenceDB) (*DisposeSequenceServer, func() error) {
+		return &DisposeSequenceServer{db: db}, func() error {
+			closed = append(closed, "server")
+			return nil
+		}
+	})
+
+	container.Resolve(&DisposeSequenceApp{})
+
+	cleanups := container.Cleanups()
+
+	if len(cleanups) != 2 || cleanups[0].Type != reflect.TypeOf(&DisposeSequenceDB{}) || cleanups[1].Type != reflect.TypeOf(&DisposeSequenceServer{}) {
+		t.Fatalf("Cleanups were not listed in construction order: %v", cleanups)
+	}
+
+	if err := cleanups[0].Run(); err != nil {
+		t.Errorf("Cleanup of db failed: %s", err)
+	}
+
+	if err := container.Dispose(); err != nil {
+		t.Fatalf("Container could not be disposed: %s", err)
+	}
+
+	if !reflect.DeepEqual(closed, []string{"db", "server"}) {
+		t.Errorf("Cleanups ran as %v", closed)
+	}
+}
@@ -0,0 +1,126 @@
+package injector
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindEnv creates a new config struct of the type the given pointer points to,
+// fills its fields tagged with env from the environment and registers it as
+// instance. Fields whose variable is not set keep their zero value.
+//
+//   type Config struct {
+//     Port    int           `env:"PORT"`
+//     Timeout time.Duration `env:"TIMEOUT"`
+//   }
+//
+//   container.BindEnv((*Config)(nil))
+//
+//   func NewServer(config *Config) *Server {…}
+//
+// Strings, booleans, numbers and durations are supported. BindEnv panics if a
+// variable cannot be parsed into its field.
+func (container *Container) BindEnv(config interface{}) {
+	if err := container.BindEnvE(config); err != nil {
+		panic(err)
+	}
+}
+
+// BindEnvE works like BindEnv but returns an error instead of panicking if
+// the config cannot be filled from the environment. No config is registered
+// in that case.
+func (container *Container) BindEnvE(config interface{}) error {
+	configType := reflect.TypeOf(config)
+
+	if configType == nil || configType.Kind() != reflect.Ptr || configType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Config '%s' must be a pointer to a struct", configType)
+	}
+
+	value := reflect.New(configType.Elem())
+
+	for i := 0; i < configType.Elem().NumField(); i++ {
+		field := configType.Elem().Field(i)
+		name, ok := field.Tag.Lookup("env")
+
+		if !ok {
+			continue
+		}
+
+		if field.PkgPath != "" {
+			return fmt.Errorf("Field '%s' of config '%s' is unexported", field.Name, configType)
+		}
+
+		env, ok := os.LookupEnv(name)
+
+		if !ok {
+			continue
+		}
+
+		if err := parseEnv(value.Elem().Field(i), env); err != nil {
+			return fmt.Errorf("Variable '%s' cannot be parsed into field '%s' of config '%s': %s", name, field.Name, configType, err)
+		}
+	}
+
+	container.RegisterInstance(value.Interface())
+
+	return nil
+}
+
+func parseEnv(field reflect.Value, env string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(env)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(env)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(env)
+
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(env, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(env, 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(env, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("Unsupported type '%s'", field.Type())
+	}
+
+	return nil
+}
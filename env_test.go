@@ -0,0 +1,69 @@
+package injector
+
+import (
+	"testing"
+	"time"
+)
+
+type EnvApp struct {
+	Server *EnvServer
+}
+
+type EnvServer struct {
+	config *EnvConfig
+}
+
+type EnvConfig struct {
+	Host    string        `env:"INJECTOR_TEST_HOST"`
+	Port    int           `env:"INJECTOR_TEST_PORT"`
+	Debug   bool          `env:"INJECTOR_TEST_DEBUG"`
+	Timeout time.Duration `env:"INJECTOR_TEST_TIMEOUT"`
+	Missing string        `env:"INJECTOR_TEST_MISSING"`
+	Ignored string
+}
+
+func NewEnvServer(config *EnvConfig) *EnvServer {
+	return &EnvServer{
+		config: config,
+	}
+}
+
+func TestBindEnv(t *testing.T) {
+	t.Setenv("INJECTOR_TEST_HOST", "localhost")
+	t.Setenv("INJECTOR_TEST_PORT", "8080")
+	t.Setenv("INJECTOR_TEST_DEBUG", "true")
+	t.Setenv("INJECTOR_TEST_TIMEOUT", "5s")
+
+	container := NewContainer()
+
+	container.Register(NewEnvServer)
+	container.BindEnv((*EnvConfig)(nil))
+
+	app := &EnvApp{}
+	container.Resolve(app)
+
+	expected := EnvConfig{
+		Host:    "localhost",
+		Port:    8080,
+		Debug:   true,
+		Timeout: 5 * time.Second,
+	}
+
+	if *app.Server.config != expected {
+		t.Errorf("Unexpected config %+v", *app.Server.config)
+	}
+}
+
+func TestBindEnvInvalid(t *testing.T) {
+	t.Setenv("INJECTOR_TEST_PORT", "http")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Invalid variable was not rejected")
+		}
+	}()
+
+	container := NewContainer()
+
+	container.BindEnv((*EnvConfig)(nil))
+}
@@ -0,0 +1,113 @@
+package injector
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MissingDependencyError is returned when no constructor provides a type that
+// is injected.
+//
+//   var missing *injector.MissingDependencyError
+//
+//   if errors.As(err, &missing) {
+//     log.Fatalf("Nothing provides %s for %v", missing.Type, missing.Chain)
+//   }
+type MissingDependencyError struct {
+	// Type is the type nothing provides
+	Type reflect.Type
+	// Chain holds the signatures of the constructors being constructed when
+	// the type was requested, starting with the outermost one. It is empty if
+	// the type was requested by a root.
+	Chain []string
+
+	message string
+}
+
+func (err *MissingDependencyError) Error() string {
+	return err.message
+}
+
+// AmbiguousDependencyError is returned when several constructors provide a
+// type that is injected as a single value.
+type AmbiguousDependencyError struct {
+	// Type is the type provided by several constructors
+	Type reflect.Type
+	// Chain holds the signatures of the constructors being constructed when
+	// the type was requested, starting with the outermost one. It is empty if
+	// the type was requested by a root.
+	Chain []string
+
+	message string
+}
+
+func (err *AmbiguousDependencyError) Error() string {
+	return err.message
+}
+
+// CycleError is returned when constructors depend on each other.
+type CycleError struct {
+	// Type is the return type of the constructor that is requested again
+	Type reflect.Type
+	// Chain holds the signatures of the constructors forming the cycle,
+	// starting with the constructor that is requested again
+	Chain []string
+
+	message string
+}
+
+func (err *CycleError) Error() string {
+	return err.message
+}
+
+// missingDependency returns a MissingDependencyError for the type.
+func missingDependency(_type reflect.Type, format string, args ...interface{}) error {
+	return &MissingDependencyError{
+		Type:    _type,
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// ambiguousDependency returns an AmbiguousDependencyError for the type.
+func ambiguousDependency(_type reflect.Type, format string, args ...interface{}) error {
+	return &AmbiguousDependencyError{
+		Type:    _type,
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// dependencyCycle returns a CycleError for the constructors forming the
+// cycle.
+func dependencyCycle(_type reflect.Type, constructors []*constructor, format string, args ...interface{}) error {
+	return &CycleError{
+		Type:    _type,
+		Chain:   signatures(constructors),
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// chainError records the pending constructors in a missing or ambiguous
+// dependency error unless a deeper constructor already did.
+func (_resolver *resolver) chainError(err error) {
+	var missing *MissingDependencyError
+	var ambiguous *AmbiguousDependencyError
+
+	switch {
+	case errors.As(err, &missing) && len(missing.Chain) == 0:
+		missing.Chain = signatures(_resolver.PendingConstructors)
+	case errors.As(err, &ambiguous) && len(ambiguous.Chain) == 0:
+		ambiguous.Chain = signatures(_resolver.PendingConstructors)
+	}
+}
+
+// signatures returns the signatures of the constructors.
+func signatures(constructors []*constructor) []string {
+	var signatures []string
+
+	for _, constructor := range constructors {
+		signatures = append(signatures, constructor.Function.Type().String())
+	}
+
+	return signatures
+}
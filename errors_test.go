@@ -0,0 +1,108 @@
+package injector
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ErrorsConfig struct{}
+
+type ErrorsStore interface {
+	Load() string
+}
+
+type ErrorsMemoryStore struct{}
+
+func (*ErrorsMemoryStore) Load() string {
+	return "memory"
+}
+
+type ErrorsFileStore struct{}
+
+func (*ErrorsFileStore) Load() string {
+	return "file"
+}
+
+type ErrorsRepo struct{}
+
+type ErrorsService struct{}
+
+type ErrorsApp struct {
+	Service *ErrorsService
+}
+
+func NewErrorsService(*ErrorsRepo) *ErrorsService {
+	return &ErrorsService{}
+}
+
+func TestMissingDependencyError(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewErrorsService, func(*ErrorsConfig) *ErrorsRepo { return &ErrorsRepo{} })
+
+	var missing *MissingDependencyError
+
+	if err := container.ResolveE(&ErrorsApp{}); !errors.As(err, &missing) {
+		t.Fatalf("Missing dependency was reported as %v", err)
+	}
+
+	expected := []string{"func(*injector.ErrorsRepo) *injector.ErrorsService", "func(*injector.ErrorsConfig) *injector.ErrorsRepo"}
+
+	if missing.Type != reflect.TypeOf(&ErrorsConfig{}) || !reflect.DeepEqual(missing.Chain, expected) {
+		t.Errorf("Missing dependency carried type '%s' and chain %v", missing.Type, missing.Chain)
+	}
+}
+
+func TestAmbiguousDependencyError(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		NewErrorsService,
+		func(ErrorsStore) *ErrorsRepo { return &ErrorsRepo{} },
+		func() *ErrorsMemoryStore { return &ErrorsMemoryStore{} },
+		func() *ErrorsFileStore { return &ErrorsFileStore{} },
+	)
+
+	var ambiguous *AmbiguousDependencyError
+
+	if err := container.ResolveE(&ErrorsApp{}); !errors.As(err, &ambiguous) {
+		t.Fatalf("Ambiguous dependency was reported as %v", err)
+	}
+
+	if ambiguous.Type != reflect.TypeOf((*ErrorsStore)(nil)).Elem() || len(ambiguous.Chain) != 2 {
+		t.Errorf("Ambiguous dependency carried type '%s' and chain %v", ambiguous.Type, ambiguous.Chain)
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewErrorsService, func(*ErrorsService) *ErrorsRepo { return &ErrorsRepo{} })
+
+	var cycle *CycleError
+
+	if err := container.ResolveE(&ErrorsApp{}); !errors.As(err, &cycle) {
+		t.Fatalf("Cycle was reported as %v", err)
+	}
+
+	expected := []string{"func(*injector.ErrorsRepo) *injector.ErrorsService", "func(*injector.ErrorsService) *injector.ErrorsRepo"}
+
+	if cycle.Type != reflect.TypeOf(&ErrorsService{}) || !reflect.DeepEqual(cycle.Chain, expected) {
+		t.Errorf("Cycle carried type '%s' and chain %v", cycle.Type, cycle.Chain)
+	}
+
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			t.Fatal("Resolve did not panic")
+		}
+
+		if err, ok := r.(error); !ok || !errors.As(err, &cycle) {
+			t.Errorf("Resolve did not panic with the cycle: %v", r)
+		}
+	}()
+
+	container.Resolve(&ErrorsApp{})
+}
@@ -0,0 +1,26 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ResolveInto works like Resolve but returns an error and takes the root
+// as typed pointer. Passing a struct value instead of a pointer is rejected
+// by the compiler.
+//
+//   app := App{}
+//   err := injector.ResolveInto(container, &app)
+func ResolveInto[T any](container *Container, root *T) error {
+	if root == nil {
+		return fmt.Errorf("Root of type '%s' must not be nil", reflect.TypeOf(root))
+	}
+
+	if _type := reflect.TypeOf(root).Elem(); _type.Kind() != reflect.Struct {
+		return fmt.Errorf("Root of type '%s' must be a struct", _type)
+	}
+
+	err := container.resolveOwned(context.Background(), []interface{}{root}, nil)
+	return err
+}
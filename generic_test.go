@@ -0,0 +1,49 @@
+package injector
+
+import (
+	"testing"
+)
+
+type ResolveIntoApp struct {
+	Foo *ResolveIntoFoo
+}
+
+type ResolveIntoFoo struct {
+	value string
+}
+
+// ResolveInto only accepts pointers, ResolveInto(container, ResolveIntoApp{})
+// does not compile.
+var _ func(*Container, *ResolveIntoApp) error = ResolveInto[ResolveIntoApp]
+
+func TestResolveInto(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *ResolveIntoFoo {
+		return &ResolveIntoFoo{value: "foo"}
+	})
+
+	app := ResolveIntoApp{}
+
+	if err := ResolveInto(container, &app); err != nil {
+		t.Fatalf("App could not be resolved: %s", err)
+	}
+
+	if app.Foo == nil || app.Foo.value != "foo" {
+		t.Errorf("Foo could not be resolved")
+	}
+}
+
+func TestResolveIntoNonStruct(t *testing.T) {
+	container := NewContainer()
+
+	value := 42
+
+	if err := ResolveInto(container, &value); err == nil {
+		t.Errorf("Non-struct root was not rejected")
+	}
+
+	if err := ResolveInto[ResolveIntoApp](container, nil); err == nil {
+		t.Errorf("Nil root was not rejected")
+	}
+}
@@ -0,0 +1,170 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterGroup registers constructors like Register and additionally makes
+// them members of the named group. A slice field tagged with the group only
+// receives the members of that group.
+//
+//   container.RegisterGroup("plugins", NewAuthPlugin, NewCachePlugin)
+func (container *Container) RegisterGroup(group string, constructors ...interface{}) {
+	if err := container.RegisterGroupE(group, constructors...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterGroupE works like RegisterGroup but returns an error instead of
+// panicking if a constructor cannot be registered. No constructor is
+// registered in that case.
+func (container *Container) RegisterGroupE(group string, constructors ...interface{}) error {
+	var members []*constructor
+
+	for _, _constructor := range constructors {
+		details, err := parseConstructor(_constructor)
+
+		if err != nil {
+			return err
+		}
+
+		details.Groups = []string{group}
+
+		members = append(members, details)
+	}
+
+	container.addConstructors(members...)
+
+	return nil
+}
+
+// GroupOption configures a group.
+type GroupOption func(*groupConfig)
+
+type groupConfig struct {
+	MinCount int
+	MaxCount int
+}
+
+// MinCount requires a group to have at least the given number of members.
+func MinCount(count int) GroupOption {
+	return func(config *groupConfig) {
+		config.MinCount = count
+	}
+}
+
+// MaxCount allows a group to have at most the given number of members.
+func MaxCount(count int) GroupOption {
+	return func(config *groupConfig) {
+		config.MaxCount = count
+	}
+}
+
+// ConfigureGroup applies the options to the named group. Injecting a group
+// whose number of members violates the configured counts fails.
+//
+//   container.ConfigureGroup("databases", injector.MinCount(1), injector.MaxCount(2))
+func (container *Container) ConfigureGroup(group string, options ...GroupOption) {
+	if container.groupConfigs == nil {
+		container.groupConfigs = make(map[string]*groupConfig)
+	}
+
+	config, ok := container.groupConfigs[group]
+
+	if !ok {
+		config = &groupConfig{}
+		container.groupConfigs[group] = config
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+}
+
+// groupConfig returns the configuration of the group from the nearest
+// container configuring it.
+func (container *Container) groupConfig(group string) *groupConfig {
+	for current := container; current != nil; current = current.parent {
+		if config, ok := current.groupConfigs[group]; ok {
+			return config
+		}
+	}
+
+	return &groupConfig{}
+}
+
+// findGroupConstructors returns the members of a group in registration order.
+func (container *Container) findGroupConstructors(group string, _type reflect.Type) ([]*constructor, error) {
+	var constructors []*constructor
+
+	for _, constructor := range container.allConstructors() {
+		if !constructor.memberOf(group) {
+			continue
+		}
+
+		if !constructor.ReturnType.AssignableTo(_type) {
+			return nil, fmt.Errorf(
+				"Constructor '%s' of group '%s' does not return type '%s'",
+				constructor.Function.Type(), group, _type,
+			)
+		}
+
+		constructors = append(constructors, constructor)
+	}
+
+	// The counts apply to the members the slice receives
+	constructors = container.filterMembers(_type, constructors)
+
+	config := container.groupConfig(group)
+
+	if len(constructors) < config.MinCount {
+		return nil, fmt.Errorf(
+			"Group '%s' of type '%s' has %d members but requires at least %d",
+			group, _type, len(constructors), config.MinCount,
+		)
+	}
+
+	if config.MaxCount > 0 && len(constructors) > config.MaxCount {
+		return nil, fmt.Errorf(
+			"Group '%s' of type '%s' has %d members but allows at most %d",
+			group, _type, len(constructors), config.MaxCount,
+		)
+	}
+
+	return constructors, nil
+}
+
+func (_resolver *resolver) resolveGroup(dependency *dependency) (reflect.Value, error) {
+	constructors, err := _resolver.Container.findGroupConstructors(dependency.Group, dependency.Type.Elem())
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return _resolver.provideSlice(dependency.Type, constructors)
+}
+
+func (constructor *constructor) memberOf(group string) bool {
+	for _, member := range constructor.Groups {
+		if member == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupNames returns the names of the named members of a group in
+// registration order. Members without a name are left out.
+func (container *Container) groupNames(group string) []string {
+	names := []string{}
+
+	for _, constructor := range container.allConstructors() {
+		if constructor.Name != "" && constructor.memberOf(group) {
+			names = append(names, constructor.Name)
+		}
+	}
+
+	return names
+}
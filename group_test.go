@@ -0,0 +1,209 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type GroupApp struct {
+	Plugins    []GroupPlugin `inject:"group:plugins"`
+	AllPlugins []GroupPlugin
+}
+
+type GroupPlugin interface {
+	Name() string
+}
+
+type GroupFirstPlugin struct {
+}
+
+func (plugin *GroupFirstPlugin) Name() string {
+	return "first_plugin"
+}
+
+type GroupSecondPlugin struct {
+}
+
+func (plugin *GroupSecondPlugin) Name() string {
+	return "second_plugin"
+}
+
+type GroupOtherPlugin struct {
+}
+
+func (plugin *GroupOtherPlugin) Name() string {
+	return "other_plugin"
+}
+
+func NewGroupFirstPlugin() *GroupFirstPlugin {
+	return &GroupFirstPlugin{}
+}
+
+func NewGroupSecondPlugin() *GroupSecondPlugin {
+	return &GroupSecondPlugin{}
+}
+
+func NewGroupOtherPlugin() *GroupOtherPlugin {
+	return &GroupOtherPlugin{}
+}
+
+func TestGroup(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterGroup("plugins", NewGroupFirstPlugin, NewGroupSecondPlugin)
+	container.Register(NewGroupOtherPlugin)
+
+	app := &GroupApp{}
+	container.Resolve(app)
+
+	plugins := app.Plugins
+
+	if len(plugins) != 2 || plugins[0].Name() != "first_plugin" || plugins[1].Name() != "second_plugin" {
+		t.Errorf("Plugins could not be resolved")
+	}
+
+	if len(app.AllPlugins) != 3 || app.AllPlugins[0] != plugins[0] || app.AllPlugins[2].Name() != "other_plugin" {
+		t.Errorf("AllPlugins could not be resolved")
+	}
+}
+
+func TestGroupNonSlice(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterGroup("plugins", NewGroupFirstPlugin)
+
+	app := &struct {
+		Plugin GroupPlugin `inject:"group:plugins"`
+	}{}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Group on non-slice field was not detected")
+		}
+	}()
+
+	container.Resolve(app)
+}
+
+type GroupCountApp struct {
+	Databases []GroupDatabase `inject:"group:databases"`
+}
+
+type GroupDatabase struct {
+	role string
+}
+
+func TestGroupMaxCount(t *testing.T) {
+	container := NewContainer()
+
+	container.ConfigureGroup("databases", MaxCount(2))
+	container.RegisterGroup("databases",
+		func() GroupDatabase { return GroupDatabase{role: "primary"} },
+		func() GroupDatabase { return GroupDatabase{role: "replica"} },
+	)
+
+	app := &GroupCountApp{}
+
+	if err := container.ResolveContext(context.Background(), app); err != nil || len(app.Databases) != 2 {
+		t.Errorf("Databases could not be resolved: %v", err)
+	}
+
+	container.RegisterGroup("databases", func() GroupDatabase { return GroupDatabase{role: "replica"} })
+
+	err := container.ResolveContext(context.Background(), &GroupCountApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "has 3 members but allows at most 2") {
+		t.Errorf("Exceeded maximum count was not detected: %v", err)
+	}
+}
+
+func TestGroupMinCount(t *testing.T) {
+	container := NewContainer()
+
+	container.ConfigureGroup("databases", MinCount(1))
+
+	if err := container.ResolveContext(context.Background(), &GroupCountApp{}); err == nil {
+		t.Errorf("Missing minimum count was not detected")
+	}
+}
+
+type GroupNamesApp struct {
+	Help *GroupNamesHelp
+}
+
+type GroupNamesHelp struct {
+	commands []string
+}
+
+type GroupNamesCommand struct {
+}
+
+func NewGroupNamesHelp(commands []string) *GroupNamesHelp {
+	return &GroupNamesHelp{
+		commands: commands,
+	}
+}
+
+func NewGroupNamesCommand() *GroupNamesCommand {
+	return &GroupNamesCommand{}
+}
+
+func TestGroupNames(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterWithTags(NewGroupNamesHelp, "names:commands")
+	container.RegisterNamed("deploy", NewGroupNamesCommand, "commands")
+	container.RegisterNamed("unrelated", NewGroupNamesCommand)
+	container.RegisterGroup("commands", NewGroupNamesCommand)
+	container.RegisterNamed("rollback", NewGroupNamesCommand, "commands")
+
+	app := &GroupNamesApp{}
+	container.Resolve(app)
+
+	commands := app.Help.commands
+
+	if len(commands) != 2 || commands[0] != "deploy" || commands[1] != "rollback" {
+		t.Errorf("Unexpected command names %v", commands)
+	}
+}
+
+func TestGroupNamesInvalidType(t *testing.T) {
+	container := NewContainer()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Names on non-string slice parameter were not detected")
+		}
+	}()
+
+	container.RegisterWithTags(func(commands []int) *GroupNamesHelp {
+		return &GroupNamesHelp{}
+	}, "names:commands")
+}
+
+func TestGroupCountFiltered(t *testing.T) {
+	container := NewContainer(FilterBy[GroupDatabase](func(registration Registration) bool {
+		return registration.Name != "standby"
+	}))
+
+	container.ConfigureGroup("databases", MinCount(1), MaxCount(2))
+	container.RegisterNamed("standby", func() GroupDatabase { return GroupDatabase{role: "standby"} }, "databases")
+
+	err := container.ResolveContext(context.Background(), &GroupCountApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "has 0 members but requires at least 1") {
+		t.Errorf("Minimum count was checked before filtering: %v", err)
+	}
+
+	container.RegisterGroup("databases",
+		func() GroupDatabase { return GroupDatabase{role: "primary"} },
+		func() GroupDatabase { return GroupDatabase{role: "replica"} },
+	)
+
+	app := &GroupCountApp{}
+
+	if err := container.ResolveContext(context.Background(), app); err != nil || len(app.Databases) != 2 {
+		t.Errorf("Maximum count was checked before filtering: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterWithHook registers a constructor like Register along with a hook
+// that is called with the constructed value once it has been built. The hook
+// must take a single parameter the return type of the constructor is
+// assignable to.
+//
+//   container.RegisterWithHook(NewServer, func(server *Server) {
+//     server.EnableMetrics()
+//   })
+func (container *Container) RegisterWithHook(_constructor interface{}, hook interface{}) {
+	details := newConstructor(_constructor)
+
+	hookType := reflect.TypeOf(hook)
+
+	if hookType == nil || hookType.Kind() != reflect.Func || hookType.NumIn() != 1 || hookType.NumOut() != 0 {
+		panic(fmt.Errorf("Hook '%s' must be a function with a single parameter and no return value", hookType))
+	}
+
+	if !details.ReturnType.AssignableTo(hookType.In(0)) {
+		panic(fmt.Errorf("Hook '%s' does not accept type '%s'", hookType, details.ReturnType))
+	}
+
+	details.Hook = reflect.ValueOf(hook)
+
+	container.addConstructors(details)
+}
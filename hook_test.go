@@ -0,0 +1,59 @@
+package injector
+
+import (
+	"testing"
+)
+
+type HookApp struct {
+	Server *HookServer
+}
+
+type HookServer struct {
+	config  *HookConfig
+	metrics bool
+}
+
+type HookConfig struct {
+}
+
+func NewHookServer(config *HookConfig) *HookServer {
+	return &HookServer{
+		config: config,
+	}
+}
+
+func NewHookConfig() *HookConfig {
+	return &HookConfig{}
+}
+
+func TestHook(t *testing.T) {
+	container := NewContainer()
+
+	calls := 0
+
+	container.RegisterWithHook(NewHookServer, func(server *HookServer) {
+		calls++
+		server.metrics = server.config != nil
+	})
+
+	container.Register(NewHookConfig)
+
+	app := &HookApp{}
+	container.Resolve(app)
+
+	if !app.Server.metrics || calls != 1 {
+		t.Errorf("Hook was not called")
+	}
+}
+
+func TestHookInvalid(t *testing.T) {
+	container := NewContainer()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Invalid hook was not detected")
+		}
+	}()
+
+	container.RegisterWithHook(NewHookServer, func(config *HookConfig) {})
+}
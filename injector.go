@@ -1,19 +1,78 @@
 package injector
 
 import (
-	"container/list"
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 )
 
 // Container keeps track of all dependencies that were registered.
 type Container struct {
+	parent       *Container
 	constructors []*constructor
+
+	diagnosticHandler func(Diagnostic)
+	keyByType         map[reflect.Type]func(reflect.Value) interface{}
+	filterByType      map[reflect.Type]func(Registration) bool
+	depthOrdered      map[reflect.Type]bool
+	groupConfigs      map[string]*groupConfig
+
+	explicitInterfaces bool
+	clock              Clock
+	strictPointers     bool
+	verboseErrors      bool
+	cycleHandler       func(Cycle) (interface{}, bool)
+	mode               interface{}
+	zeroAllocation     bool
+	strictExports      bool
+
+	keyedConstructors []*keyedConstructor
+	keyedResolver     *resolver
+
+	// activationResolver is the resolver of the last resolution sharing the
+	// singletons of the container, deferred types are activated with it
+	activationResolver *resolver
+
+	resolvedConstructors []*constructor
+	resolvedReused       map[*constructor]bool
+	resolvedValues       map[*constructor]reflect.Value
+	resolvedProviders    map[interface{}]*constructor
+	resolvedWarnings     []error
+
+	// lock guards the cleanups kept until the container is disposed, the
+	// counts of the constructed instances and the singletons and pointers
+	// shared across resolutions along with the constructors registrations
+	// changed, and how many changes of each ancestor they account for
+	lock           sync.Mutex
+	cleanups       []Cleanup
+	disposed       bool
+	instanceCounts map[reflect.Type]int
+	singletons     map[*constructor]singleton
+	addressed      map[reflect.Type]reflect.Value
+	changes        []*constructor
+	seenChanges    map[*Container]int
 }
 
+// Option configures a container.
+type Option func(*Container)
+
 // NewContainer creates a new empty container.
-func NewContainer() *Container {
-	return &Container{}
+func NewContainer(options ...Option) *Container {
+	container := &Container{
+		keyByType:    make(map[reflect.Type]func(reflect.Value) interface{}),
+		filterByType: make(map[reflect.Type]func(Registration) bool),
+		depthOrdered: make(map[reflect.Type]bool),
+	}
+
+	for _, option := range options {
+		option(container)
+	}
+
+	return container
 }
 
 // Register registers new dependencies based on constructor functions. A
@@ -29,192 +88,1114 @@ func NewContainer() *Container {
 //
 //   func NewBar(foo *Foo) *Baz {…} // Inject Foo dependency
 //
+// A struct or a pointer to a struct without a constructor of its own is
+// injected from the constructor of the pointer or struct respectively, see
+// WithoutPointerConversion.
+//
 // An interface. Exactly one dependency must be registered that returns
 // an instance of a struct that implements that interface.
 //
 //   func NewBar(foo Foo) *Baz {…} // Inject dependency that implements the Foo interface
 //
-// A slice type using an interface. At least one dependency must be registered that returns
-// an instance of a struct that implements that interface.
+// A constructor taking a parameter of its own return type is a decorator. It
+// receives the value of the constructor it decorates and its return value is
+// injected instead. Decorators are applied in registration order. A decorator
+// without another constructor of its type is reported as a self-cycle.
+//
+//   func NewCachingRepo(inner Repo) Repo {…} // Decorate the registered Repo
+//
+// A decorator of a concrete type also applies wherever that type is injected
+// for an interface, so consumers of the interface and of the concrete type
+// share the single decorated value.
+//
+// A slice type using an interface. All dependencies that are registered and
+// return an instance of a struct that implements that interface are injected.
 //
 //   func NewBar(foos []Foo) *Baz {…} // Inject all dependencies that implement the Foo interface
+//
+// The values of a slice are in registration order, instances registered with
+// RegisterInstance are members like the values of constructors.
+//
+// A constructor may return a cleanup function as second value that may return
+// an error. The cleanup functions of a resolution are run by the Disposer
+// returned from ResolveWithDisposer or by Dispose of the container.
+//
+//   func NewDB(config *Config) (*DB, func() error) {…}
+//
+// A constructor may return an error as second value instead. A non-nil error
+// fails the resolution, see RegisterWithRetry for constructors that may fail
+// temporarily.
+//
+//   func NewDB(config *Config) (*DB, error) {…}
+//
+// Type aliases are identical to the type they alias, a parameter of type
+// Handler declared as `type Handler = http.Handler` receives the value of a
+// http.Handler constructor. Defined types like `type Port int` are distinct
+// from their underlying type, except that interfaces are still satisfied by
+// every implementation.
+//
+// Pointers to interfaces or slices, nested slices and maps of interfaces are
+// not supported and cause Register to panic, Provide returns an error
+// instead.
 func (container *Container) Register(constructors ...interface{}) {
 	for _, _constructor := range constructors {
-		_type := reflect.TypeOf(_constructor)
+		container.addConstructors(newConstructor(_constructor))
+	}
+}
+
+// addConstructors adds the constructors to the container. The singletons of
+// earlier resolutions depending on their types are dropped, since a new
+// constructor may shadow their dependencies or add to the slices they
+// received.
+func (container *Container) addConstructors(constructors ...*constructor) {
+	container.constructors = append(container.constructors, constructors...)
+	container.dropSingletons(constructors...)
+}
+
+func newConstructor(_constructor interface{}) *constructor {
+	details, err := parseConstructor(_constructor)
 
-		if _type.Kind() != reflect.Func {
-			panic(fmt.Sprintf("Constructor '%s' is not a function", _type))
+	if err != nil {
+		panic(err)
+	}
+
+	return details
+}
+
+// parseConstructor validates the signature of the constructor and returns its
+// details or an error describing why it cannot be registered.
+func parseConstructor(_constructor interface{}) (*constructor, error) {
+	_type := reflect.TypeOf(_constructor)
+
+	if _type == nil || _type.Kind() != reflect.Func {
+		return nil, fmt.Errorf("Constructor '%s' is not a function", _type)
+	}
+
+	cleanup := _type.NumOut() == 2 && (_type.Out(1) == cleanupType || _type.Out(1) == cleanupErrorType)
+	returnsError := _type.NumOut() == 2 && _type.Out(1) == errorType
+
+	if _type.NumOut() != 1 && !cleanup && !returnsError {
+		return nil, fmt.Errorf("Constructor '%s' must have single return value optionally followed by a cleanup function or an error", _type)
+	}
+
+	function := reflect.ValueOf(_constructor)
+
+	var params []reflect.Type
+	var dependencies []*dependency
+
+	for i := 0; i < _type.NumIn(); i++ {
+		param := _type.In(i)
+
+		if !supportedParameter(param) {
+			return nil, fmt.Errorf("Unsupported parameter kind '%s' at position %d of constructor '%s'", param, i, _type)
 		}
 
-		if _type.NumOut() != 1 {
-			panic(fmt.Sprintf("Constructor '%s' must have single return value", _type))
+		params = append(params, param)
+		dependencies = append(dependencies, &dependency{Type: param})
+	}
+
+	returnType := _type.Out(0)
+
+	decorator := false
+
+	for _, param := range params {
+		if param == returnType {
+			decorator = true
 		}
+	}
 
-		function := reflect.ValueOf(_constructor)
+	return &constructor{
+		Function:     function,
+		Parameters:   params,
+		Dependencies: dependencies,
+		ReturnType:   returnType,
+		Decorator:    decorator,
+		Cleanup:      cleanup,
+		ReturnsError: returnsError,
+	}, nil
+}
 
-		var params []reflect.Type
+// RegisterInstance registers already constructed values as dependencies. An
+// instance is injected exactly like the return value of a constructor that
+// takes no parameters.
+//
+//   container.RegisterInstance(&Config{Port: 8080})
+//
+// Instances of named value types like time.Time or a UserID string type are
+// injected for exactly their type, never for their underlying type.
+//
+//   container.RegisterInstance(UserID("alice"))
+//
+// This also covers values shared by all consumers like an event channel. A
+// bidirectional channel can be injected as receive-only or send-only channel.
+//
+//   container.RegisterInstance(make(chan Event, 16))
+//
+//   func NewPublisher(events chan<- Event) *Publisher {…}
+//   func NewSubscriber(events <-chan Event) *Subscriber {…}
+//
+// An instance held by an interface variable is registered with its dynamic
+// type, the interface type of the variable is lost. It is still injected for
+// that interface and every other type its dynamic type is assignable to.
+//
+//   var logger Logger = &FileLogger{}
+//   container.RegisterInstance(logger) // Injected as Logger and *FileLogger
+//
+// Instances are owned by the caller. Dispose never closes them, even if they
+// implement io.Closer, only cleanup functions returned by constructors are
+// run. A shared connection registered as instance is therefore not closed
+// twice.
+func (container *Container) RegisterInstance(instances ...interface{}) {
+	for _, instance := range instances {
+		value := reflect.ValueOf(instance)
 
-		for i := 0; i < _type.NumIn(); i++ {
-			param := _type.In(i)
-			params = append(params, param)
+		if !value.IsValid() {
+			panic(errors.New("Instance must not be nil"))
 		}
 
-		returnType := _type.Out(0)
+		returnType := value.Type()
+
+		function := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{returnType}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{value}
+			},
+		)
 
 		details := &constructor{
 			Function:   function,
-			Parameters: params,
 			ReturnType: returnType,
+			Instance:   value,
 		}
 
-		container.constructors = append(container.constructors, details)
+		container.addConstructors(details)
 	}
 }
 
 // Resolve wires together the object graph starting with the fields
 // in the given struct instance.
+//
+// A slice field can be limited to the members of a group using a struct tag
+// and ordered by the names of its members.
+// An interface field can be bound to a specific implementation by naming the
+// type returned by its constructor, the name it was registered with or the
+// tags it was registered with.
+//
+//   type App struct {
+//     Plugins []Plugin `inject:"group:plugins"`
+//     Filters []Filter `inject:"order=auth,logging"`
+//     Store   Store    `inject:"type=*redis.Store"`
+//     Cache   Cache    `inject:"name=sessions"`
+//     Mailer  Mailer   `inject:"env=prod"`
+//   }
+//
+// The values constructed by a resolution are singletons of the container.
+// Later resolutions of the container and its children, Invoke and the
+// dependencies of keyed constructors reuse them instead of invoking their
+// constructors again, so two roots share one database pool even if they are
+// resolved separately. A child only reuses a value if it constructs none of
+// the types the value was constructed from itself. Values of transient
+// constructors are never reused. Registering another constructor drops the
+// singletons of the container and its children that depend on its type,
+// directly or through other constructors, so the next resolution constructs
+// them again. Unrelated singletons are kept.
+// ResolveWith with overrides, ResolveSeeded, ResolveReusing and
+// ResolveWithDisposer construct their graphs separately.
 func (container *Container) Resolve(root interface{}) {
-	resolver := newResolver(container)
+	if err := container.ResolveE(root); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveWith works like Resolve but uses the given values for their types
+// instead of constructing them. The overrides only apply to this resolution,
+// the container itself is left untouched.
+//
+//   container.ResolveWith(&app, map[reflect.Type]interface{}{
+//     reflect.TypeOf((*Clock)(nil)).Elem(): &FakeClock{},
+//   })
+func (container *Container) ResolveWith(root interface{}, overrides map[reflect.Type]interface{}) {
+	if err := container.ResolveWithE(root, overrides); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveWithE works like ResolveWith but returns an error instead of panicking
+// if an override is not assignable to its type or the resolution fails.
+func (container *Container) ResolveWithE(root interface{}, overrides map[reflect.Type]interface{}) error {
+	var prepare func(*resolver) error
+
+	if len(overrides) > 0 {
+		prepare = func(_resolver *resolver) error {
+			for _type, override := range overrides {
+				if err := _resolver.override(_type, override); err != nil {
+					return err
+				}
+			}
 
-	rootType := reflect.TypeOf(root).Elem()
+			return nil
+		}
+	}
+
+	return container.resolveOwned(context.Background(), []interface{}{root}, prepare)
+}
+
+// ResolveSeeded works like ResolveWith but also skips the constructors
+// returning a seeded type entirely. Wherever their value would be injected,
+// e.g. for an interface, a name or a group, the seed is injected instead, so
+// the subtree below these constructors is never built.
+//
+//   container.ResolveSeeded(&app, map[reflect.Type]interface{}{
+//     reflect.TypeOf(&DB{}): db,
+//   })
+//
+// A seed of a type without a constructor is a member of the slices of the
+// interfaces it implements, following the values of the constructors.
+func (container *Container) ResolveSeeded(root interface{}, seeds map[reflect.Type]interface{}) {
+	if err := container.ResolveSeededE(root, seeds); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveSeededE works like ResolveSeeded but returns an error instead of
+// panicking if a seed is not assignable to its type or the resolution fails.
+func (container *Container) ResolveSeededE(root interface{}, seeds map[reflect.Type]interface{}) error {
+	prepare := func(_resolver *resolver) error {
+		for _type, seed := range seeds {
+			if err := _resolver.seed(_type, seed); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return container.resolveOwned(context.Background(), []interface{}{root}, prepare)
+}
+
+// ResolveReusing works like ResolveSeeded but seeds the resolution with the
+// fields of an already resolved root, so a secondary root reuses the values
+// constructed for the first one. Every field is seeded for its declared type
+// and the dynamic type of its value, fields with zero values are skipped.
+//
+//   container.Resolve(&core)
+//   container.ResolveReusing(&feature, &core)
+//
+// Fields holding different values for the same type cause a panic, as do
+// unexported fields.
+func (container *Container) ResolveReusing(root interface{}, existing interface{}) {
+	if err := container.ResolveReusingE(root, existing); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveReusingE works like ResolveReusing but returns an error instead of
+// panicking if the existing root cannot be reused or the resolution fails.
+func (container *Container) ResolveReusingE(root interface{}, existing interface{}) error {
+	prepare := func(_resolver *resolver) error {
+		seeds := make(map[reflect.Type]reflect.Value)
+
+		existingValue := reflect.ValueOf(existing).Elem()
+
+		for i := 0; i < existingValue.NumField(); i++ {
+			field := existingValue.Type().Field(i)
+
+			if field.PkgPath != "" {
+				return fmt.Errorf("Field '%s' of root type '%s' is unexported and cannot be reused", field.Name, existingValue.Type())
+			}
+
+			value := existingValue.Field(i)
+
+			if value.IsZero() {
+				continue
+			}
+
+			types := []reflect.Type{field.Type}
+
+			if value.Kind() == reflect.Interface {
+				types = append(types, value.Elem().Type())
+			}
+
+			for _, _type := range types {
+				if seeded, ok := seeds[_type]; ok && !sameValue(seeded, value) {
+					return fmt.Errorf("Root type '%s' holds different values for type '%s' and cannot be reused", existingValue.Type(), _type)
+				}
+
+				seeds[_type] = value
+			}
+		}
+
+		for _type, seed := range seeds {
+			if err := _resolver.seed(_type, seed.Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return container.resolveOwned(context.Background(), []interface{}{root}, prepare)
+}
+
+// sameValue reports whether both values are equal, values are considered
+// different if they are not comparable.
+func sameValue(value, other reflect.Value) bool {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if other.Kind() == reflect.Interface {
+		other = other.Elem()
+	}
+
+	return value.Type().Comparable() && other.Type().Comparable() && value.Interface() == other.Interface()
+}
+
+// ResolveAll works like Resolve for several roots at once. All roots share
+// the same singletons, as if their fields were declared in a single struct.
+//
+//   container.ResolveAll(&server, &workers, &admin)
+func (container *Container) ResolveAll(roots ...interface{}) {
+	if err := container.ResolveAllE(roots...); err != nil {
+		panic(err)
+	}
+}
+
+// ResolveAllE works like ResolveAll but returns an error instead of panicking
+// if one of the roots cannot be resolved.
+func (container *Container) ResolveAllE(roots ...interface{}) error {
+	return container.resolveOwned(context.Background(), roots, nil)
+}
+
+// ResolveContext works like Resolve but returns an error instead of
+// panicking. The context is checked before each constructor is invoked, so a
+// canceled context aborts the remaining resolution with an error wrapping
+// ctx.Err().
+func (container *Container) ResolveContext(ctx context.Context, root interface{}) error {
+	err := container.resolveOwned(ctx, []interface{}{root}, nil)
+	return err
+}
+
+// resolve wires the roots and returns the cleanup functions of the invoked
+// constructors in construction order. The prepare function, if any, sets up
+// the resolver before the roots are resolved.
+func (container *Container) resolve(ctx context.Context, roots []interface{}, prepare func(*resolver) error) ([]Cleanup, error) {
+	if err := container.checkDisposed(); err != nil {
+		return nil, err
+	}
+
+	if err := checkRoots(roots); err != nil {
+		return nil, err
+	}
+
+	container.reportEmptyRoots(roots)
+
+	// Containers holding nothing but instances have no graph to walk
+	if len(roots) == 1 && prepare == nil && container.instancesOnly() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		constructors, ok, err := container.resolveInstances(roots[0])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			valueByConstructor := make(map[*constructor]reflect.Value)
+
+			for _, constructor := range constructors {
+				valueByConstructor[constructor] = constructor.Instance
+			}
+
+			container.remember(constructors, valueByConstructor, nil, nil)
+			container.reportSharedInstances(valueByConstructor)
+			return nil, nil
+		}
+	}
+
+	resolver := newResolver(ctx, container)
+
+	// Overrides only apply to a single resolution and must not be mixed with
+	// the singletons of the container
+	resolver.Singletons = prepare == nil
+
+	if prepare != nil {
+		if err := prepare(resolver); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := container.resolveGraph(resolver, roots); err != nil {
+		return nil, container.abort(err, resolver)
+	}
+
+	// Overrides and seeds must not leak into activations
+	if resolver.Singletons {
+		container.lock.Lock()
+		container.activationResolver = resolver
+		container.lock.Unlock()
+	}
+
+	return resolver.Cleanups, nil
+}
+
+func (container *Container) resolveGraph(resolver *resolver, roots []interface{}) error {
+	valuesByRoot := make([][]reflect.Value, len(roots))
+
+	for r, root := range roots {
+		values, err := resolver.resolveFields(root)
+
+		if err != nil {
+			return err
+		}
+
+		valuesByRoot[r] = values
+	}
+
+	// Only touch the roots once the whole graph could be resolved
+	for r, root := range roots {
+		rootValue := reflect.ValueOf(root).Elem()
+
+		for i, value := range valuesByRoot[r] {
+			rootValue.Field(i).Set(value)
+		}
+	}
+
+	if resolver.Singletons {
+		container.cacheSingletons(resolver, resolver.InvokedConstructors)
+	}
+
+	container.remember(resolver.InvokedConstructors, resolver.ValueByConstructor, resolver.Reused, resolver.Warnings)
+	container.reportSharedInstances(resolver.ValueByConstructor)
+
+	return nil
+}
+
+// checkRoots returns an error if a root is not a non-nil pointer to a struct.
+func checkRoots(roots []interface{}) error {
+	for _, root := range roots {
+		rootValue := reflect.ValueOf(root)
+
+		if !rootValue.IsValid() || rootValue.Kind() != reflect.Ptr || rootValue.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("Root '%s' must be a non-nil pointer to a struct", reflect.TypeOf(root))
+		}
+	}
+
+	return nil
+}
+
+// resolveFields resolves the values of all fields of the root without
+// touching it.
+func (_resolver *resolver) resolveFields(root interface{}) ([]reflect.Value, error) {
+	rootType := reflect.ValueOf(root).Elem().Type()
+
+	_resolver.RootType = reflect.TypeOf(root)
+
+	var values []reflect.Value
 
-	// Add all types that should be resolved
 	for i := 0; i < rootType.NumField(); i++ {
-		structField := rootType.Field(i)
-		structFieldType := structField.Type
+		// Embedded fields are set like named fields if they are exported
+		if field := rootType.Field(i); field.PkgPath != "" {
+			return nil, fmt.Errorf("Field '%s' of root type '%s' is unexported and cannot be injected", field.Name, rootType)
+		}
+
+		dependency, err := newFieldDependency(rootType.Field(i))
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Fields of deferred types are left empty until they are activated
+		if _resolver.deferred(dependency.Type) {
+			values = append(values, reflect.Zero(dependency.Type))
+			continue
+		}
+
+		value, err := _resolver.resolveDependency(dependency)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Guard the root against inconsistencies of the resolver itself
+		if !value.IsValid() {
+			return nil, fmt.Errorf("Internal error: field '%s' of type '%s' resolved to no value", rootType.Field(i).Name, dependency.Type)
+		}
 
-		resolver.resolveType(structFieldType)
+		values = append(values, value)
 	}
 
-	// Resolve all types that were added initially
+	return values, nil
+}
+
+func (container *Container) instancesOnly() bool {
+	for _, constructor := range container.allConstructors() {
+		if !constructor.Instance.IsValid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveInstances assigns the root fields directly from the registered
+// instances without walking the object graph. It returns the instances that
+// were used in the order of their first use. It reports false without
+// touching the root if a field has no instance, so the object graph is walked
+// instead.
+func (container *Container) resolveInstances(root interface{}) ([]*constructor, bool, error) {
+	rootValue := reflect.ValueOf(root).Elem()
+	rootType := rootValue.Type()
+
+	var values []reflect.Value
+	var used []*constructor
+	var singleImplementations []reflect.Type
+
+	usedConstructors := make(map[*constructor]bool)
+	reported := make(map[reflect.Type]bool)
+
 	for i := 0; i < rootType.NumField(); i++ {
-		structField := rootType.Field(i)
-		structFieldType := structField.Type
+		if field := rootType.Field(i); field.PkgPath != "" {
+			return nil, false, fmt.Errorf("Field '%s' of root type '%s' is unexported and cannot be injected", field.Name, rootType)
+		}
 
-		rootValue := reflect.ValueOf(root).Elem()
-		rootValue.Field(i).Set(resolver.ValuesByType[structFieldType][0])
+		dependency, err := newFieldDependency(rootType.Field(i))
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if dependency.GroupNames != "" {
+			names := container.groupNames(dependency.GroupNames)
+			values = append(values, reflect.ValueOf(names).Convert(dependency.Type))
+			continue
+		}
+
+		if value, ok := container.builtin(dependency.Type); ok && !dependency.tagged() {
+			values = append(values, value)
+			continue
+		}
+
+		constructors, err := container.findDependencyConstructors(dependency)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if dependency.Type.Kind() == reflect.Slice && container.sorted(dependency.Type.Elem()) {
+			return nil, false, nil
+		}
+
+		if dependency.Type.Kind() != reflect.Slice {
+			if len(constructors) == 0 {
+				return nil, false, nil
+			}
+
+			if nearest := container.nearestConstructors(constructors); len(nearest) == 1 {
+				constructors = nearest
+			}
+
+			if err := container.checkSingle(dependency.Type, len(constructors)); err != nil {
+				return nil, false, err
+			}
+		}
+
+		var instances []reflect.Value
+
+		for _, constructor := range constructors {
+			instances = append(instances, constructor.Instance)
+
+			if !usedConstructors[constructor] {
+				usedConstructors[constructor] = true
+				used = append(used, constructor)
+			}
+		}
+
+		if dependency.Type.Kind() == reflect.Slice {
+			values = append(values, container.sliceOf(dependency.Type, instances))
+
+			if len(instances) == 1 && !dependency.tagged() && dependency.Type.Elem().Kind() == reflect.Interface && !reported[dependency.Type] {
+				reported[dependency.Type] = true
+				singleImplementations = append(singleImplementations, dependency.Type)
+			}
+		} else {
+			values = append(values, instances[0])
+		}
+	}
+
+	for i, value := range values {
+		rootValue.Field(i).Set(value)
 	}
+
+	// Reported like by resolveType once the fast path is certain to be taken
+	for _, _type := range singleImplementations {
+		container.reportSingleImplementation(_type)
+	}
+
+	return used, true, nil
 }
 
 func (container *Container) findConstructors(_type reflect.Type) []*constructor {
 	var constructors []*constructor
 
-	for _, constructor := range container.constructors {
-		if constructor.ReturnType.AssignableTo(_type) {
-			constructors = append(constructors, constructor)
+	for _, constructor := range container.allConstructors() {
+		if constructor.Decorator || !constructor.ReturnType.AssignableTo(_type) || !container.activeVariant(constructor) {
+			continue
+		}
+
+		if container.explicitInterfaces && _type.Kind() == reflect.Interface && constructor.ReturnType != _type {
+			continue
 		}
+
+		constructors = append(constructors, constructor)
 	}
 
+	sortByPriority(constructors)
+
 	return constructors
 }
 
+func (container *Container) findDependencyConstructors(dependency *dependency) ([]*constructor, error) {
+	if len(dependency.Order) > 0 {
+		return container.findOrderedConstructors(dependency)
+	}
+
+	if dependency.ConcreteType != "" {
+		concrete, err := container.findConcreteConstructor(dependency)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*constructor{concrete}, nil
+	}
+
+	if dependency.Name != "" {
+		named, err := container.findNamedConstructor(dependency.Name, dependency.Type)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*constructor{named}, nil
+	}
+
+	if dependency.Group != "" {
+		return container.findGroupConstructors(dependency.Group, dependency.Type.Elem())
+	}
+
+	if len(dependency.Tags) > 0 {
+		return container.findTaggedConstructors(dependency)
+	}
+
+	if dependency.Type.Kind() == reflect.Slice {
+		return container.filterMembers(dependency.Type.Elem(), container.findConstructors(dependency.Type.Elem())), nil
+	}
+
+	return container.findConstructors(dependency.Type), nil
+}
+
 type constructor struct {
-	Function   reflect.Value
-	Parameters []reflect.Type
-	ReturnType reflect.Type
+	Function     reflect.Value
+	Parameters   []reflect.Type
+	Dependencies []*dependency
+	ReturnType   reflect.Type
+	Instance     reflect.Value
+	Name         string
+	Groups       []string
+	Decorator    bool
+	Hook         reflect.Value
+	Assertion    reflect.Value
+	Defaults     map[int]func() interface{}
+	Cleanup      bool
+	ReturnsError bool
+	Tags         map[string]string
+	Priority     int
+	Attempts     int
+	Backoff      time.Duration
+	Pure         bool
+	Transient    bool
+	Deferred     bool
+	Variant      interface{}
 }
 
 type valuesByType map[reflect.Type][]reflect.Value
 
-func (_valuesByType valuesByType) hasValue(_type reflect.Type, value reflect.Value) bool {
-	for _, value := range _valuesByType[_type] {
-		if value == value {
-			return true
+type resolver struct {
+	Container *Container
+	Context   context.Context
+
+	// Lock guards resolutions happening after the initial one. The
+	// constructors invoked meanwhile can lock it again.
+	Lock reentrantLock
+
+	ValuesByType        valuesByType
+	ConstructorsByType  map[reflect.Type][]*constructor
+	OverriddenTypes     map[reflect.Type]bool
+	ValueByConstructor  map[*constructor]reflect.Value
+	PendingConstructors []*constructor
+	PendingDepths       []int
+	Depth               map[*constructor]int
+	Requirements        map[*constructor][]*constructor
+	Reused              map[*constructor]bool
+	InvokedConstructors []*constructor
+	DecoratedValues     map[*constructor]reflect.Value
+	DecoratedBases      map[*constructor]*constructor
+	AddressedValues     map[reflect.Type]reflect.Value
+	Cleanups            []Cleanup
+	Warnings            []error
+
+	// RootType is the type of the root whose fields are being resolved
+	RootType reflect.Type
+
+	// Tracer records the invoked constructors if the resolution is traced
+	Tracer *tracer
+
+	// DryRun skips calling the constructors and uses zero values instead
+	DryRun bool
+
+	// Activating allows deferred constructors to be invoked
+	Activating bool
+
+	// Singletons reuses the values cached by the container and caches the
+	// values of a successful resolution
+	Singletons bool
+
+	// InvokePure calls pure constructors during a dry run if none of their
+	// dependencies is a placeholder
+	InvokePure   bool
+	Placeholders int
+	Placeholder  map[*constructor]bool
+}
+
+func newResolver(ctx context.Context, container *Container) *resolver {
+	return &resolver{
+		Container: container,
+		Context:   ctx,
+
+		ValuesByType:       make(map[reflect.Type][]reflect.Value),
+		ConstructorsByType: make(map[reflect.Type][]*constructor),
+		OverriddenTypes:    make(map[reflect.Type]bool),
+		ValueByConstructor: make(map[*constructor]reflect.Value),
+		DecoratedValues:    make(map[*constructor]reflect.Value),
+		DecoratedBases:     make(map[*constructor]*constructor),
+		AddressedValues:    make(map[reflect.Type]reflect.Value),
+		Placeholder:        make(map[*constructor]bool),
+		Depth:              make(map[*constructor]int),
+		Requirements:       make(map[*constructor][]*constructor),
+		Reused:             make(map[*constructor]bool),
+	}
+}
+
+func (_resolver *resolver) override(_type reflect.Type, override interface{}) error {
+	value := reflect.ValueOf(override)
+
+	if !value.IsValid() || !value.Type().AssignableTo(_type) {
+		return fmt.Errorf("Override '%T' is not assignable to type '%s'", override, _type)
+	}
+
+	_resolver.ValuesByType[_type] = []reflect.Value{value}
+	_resolver.OverriddenTypes[_type] = true
+
+	return nil
+}
+
+// overriddenMembers returns the overridden values assignable to the element
+// type of a slice whose types have no constructor of their own, ordered by
+// their types. Values of types with a constructor are members in place of
+// the value of that constructor if they are seeded.
+func (_resolver *resolver) overriddenMembers(_type reflect.Type) []reflect.Value {
+	var types []reflect.Type
+
+	for overridden := range _resolver.OverriddenTypes {
+		if overridden != _type && overridden.Kind() != reflect.Interface && overridden.AssignableTo(_type) && len(_resolver.Container.findConstructors(overridden)) == 0 {
+			types = append(types, overridden)
 		}
 	}
 
-	return false
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	var members []reflect.Value
+
+	for _, overridden := range types {
+		members = append(members, _resolver.ValuesByType[overridden]...)
+	}
+
+	return members
 }
 
-type resolver struct {
-	Container *Container
+// seed overrides the type and marks all constructors returning it as invoked
+// with the seeded value.
+func (_resolver *resolver) seed(_type reflect.Type, seed interface{}) error {
+	if err := _resolver.override(_type, seed); err != nil {
+		return err
+	}
 
-	ValuesByType       valuesByType
-	VisitedTypes       map[reflect.Type]bool
-	ValueByConstructor map[*constructor]reflect.Value
+	for _, constructor := range _resolver.Container.allConstructors() {
+		if !constructor.Decorator && constructor.ReturnType == _type {
+			_resolver.ValueByConstructor[constructor] = reflect.ValueOf(seed)
+		}
+	}
+
+	return nil
 }
 
-func (_resolver *resolver) resolveType(_type reflect.Type) {
-	stack := list.New()
-	stack.PushFront(_type)
+func (_resolver *resolver) resolveDependency(dependency *dependency) (reflect.Value, error) {
+	if dependency.ConcreteType != "" {
+		constructor, err := _resolver.Container.findConcreteConstructor(dependency)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return _resolver.provide(constructor, dependency.Type)
+	}
+
+	if dependency.Name != "" {
+		constructor, err := _resolver.Container.findNamedConstructor(dependency.Name, dependency.Type)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
 
-	container := _resolver.Container
+		return _resolver.provide(constructor, dependency.Type)
+	}
 
-	for stack.Len() > 0 {
-		typeElement := stack.Front()
+	if len(dependency.Order) > 0 {
+		return _resolver.resolveOrdered(dependency)
+	}
 
-		rawType := typeElement.Value.(reflect.Type)
-		_type := innerType(rawType)
+	if dependency.Group != "" {
+		return _resolver.resolveGroup(dependency)
+	}
 
-		_resolver.VisitedTypes[_type] = true
+	if dependency.GroupNames != "" {
+		names := _resolver.Container.groupNames(dependency.GroupNames)
+		return reflect.ValueOf(names).Convert(dependency.Type), nil
+	}
 
-		// Resolve type by invoking all its constructors
+	if len(dependency.Tags) > 0 {
+		return _resolver.resolveTagged(dependency)
+	}
 
-		constructors := container.findConstructors(_type)
+	return _resolver.resolveType(dependency.Type)
+}
 
-		if len(constructors) == 0 && rawType.Kind() != reflect.Slice {
-			panic(fmt.Sprintf("No constructor defined for type '%s'", _type))
+// resolveType returns the single value of the given type, or all values of its
+// element type if it is a slice.
+func (_resolver *resolver) resolveType(_type reflect.Type) (reflect.Value, error) {
+	if value, ok, err := _resolver.synthesize(_type); ok {
+		return value, err
+	}
+
+	if value, ok := _resolver.Container.builtin(_type); ok {
+		return value, nil
+	}
+
+	if value, ok, err := _resolver.convertPointer(_type); ok {
+		return value, err
+	}
+
+	if value, ok, err := _resolver.addressForInterface(_type); ok {
+		return value, err
+	}
+
+	if value, ok, err := _resolver.assertInterface(_type); ok {
+		return value, err
+	}
+
+	if _type.Kind() == reflect.Slice {
+		if _, ok := _resolver.Container.filterByType[_type.Elem()]; ok || _resolver.Container.depthOrdered[_type.Elem()] {
+			return _resolver.resolveFiltered(_type)
 		}
 
-		var pendingConstructors []*constructor
+		_, cached := _resolver.ValuesByType[_type.Elem()]
 
-		if _type.Kind() != reflect.Slice {
-			for _, constructor := range constructors {
-				if value, ok := _resolver.constructorInvoked(constructor, _type); ok {
-					if !_resolver.ValuesByType.hasValue(_type, value) {
-						_resolver.ValuesByType[_type] = append(_resolver.ValuesByType[_type], value)
-					}
-				} else if _resolver.constructorInvokable(constructor) {
-					value := _resolver.invokeConstructor(constructor, _type)
-					_resolver.ValuesByType[_type] = append(_resolver.ValuesByType[_type], value)
-					_resolver.ValueByConstructor[constructor] = value
-				} else {
-					pendingConstructors = append(pendingConstructors, constructor)
-				}
-			}
+		values, err := _resolver.resolveValues(_type.Elem())
 
-			if len(pendingConstructors) == 0 {
-				stack.Remove(typeElement)
-				continue
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if !_resolver.OverriddenTypes[_type.Elem()] {
+			values = append(values, _resolver.overriddenMembers(_type.Elem())...)
+		}
+
+		if !cached && len(values) == 1 && _type.Elem().Kind() == reflect.Interface {
+			_resolver.Container.reportSingleImplementation(_type)
+		}
+
+		return _resolver.sortSlice(_resolver.Container.sliceOf(_type, values))
+	}
+
+	if value, ok := _resolver.allocateZero(_type); ok {
+		return value, nil
+	}
+
+	// Check for ambiguity before constructing anything, values cached by a
+	// slice of the type are still subject to the selection of a single value
+	if _, cached := _resolver.ValuesByType[_type]; !cached || !_resolver.OverriddenTypes[_type] {
+		constructors := _resolver.Container.findConstructors(_type)
+
+		// A child shadows its ancestors before priorities are compared
+		nearest := _resolver.Container.nearestConstructors(constructors)
+
+		if len(nearest) == 1 && len(constructors) > 1 {
+			return _resolver.provide(nearest[0], _type)
+		}
+
+		if preferred := highestPriority(nearest); preferred != nil {
+			return _resolver.provide(preferred, _type)
+		}
+
+		if !cached {
+			if err := _resolver.Container.checkSingle(_type, len(constructors)); err != nil {
+				return reflect.Value{}, err
 			}
 		}
+	}
 
-		// Resolve missing parameters of pending constructors
+	values, err := _resolver.resolveValues(_type)
 
-		for _, dep := range pendingConstructors {
+	if err != nil {
+		return reflect.Value{}, err
+	}
 
-			for _, param := range dep.Parameters {
-				visited := _resolver.VisitedTypes[param]
-				resolved := _resolver.typeResolved(param)
+	if len(values) == 0 && len(_resolver.Container.findConstructors(_type)) > 0 {
+		return reflect.Value{}, fmt.Errorf("Internal error: type '%s' has a constructor but resolved to no value", _type)
+	}
 
-				if visited && !resolved {
-					panic(fmt.Sprintf(
-						"Cycle detected for parameter '%s' of constructor '%s' while resolving type '%s'.",
-						param, dep.Function.Type(), _type,
-					))
-				}
+	if err := _resolver.Container.checkSingle(_type, len(values)); err != nil {
+		return reflect.Value{}, err
+	}
 
-				if !visited {
-					stack.PushFront(param)
-				}
+	return values[0], nil
+}
+
+// resolveValues invokes all constructors of the given type once.
+func (_resolver *resolver) resolveValues(_type reflect.Type) ([]reflect.Value, error) {
+	if values, ok := _resolver.ValuesByType[_type]; ok {
+		for _, constructor := range _resolver.ConstructorsByType[_type] {
+			if _resolver.Placeholder[constructor] {
+				_resolver.Placeholders++
 			}
+
+			_resolver.require(constructor)
 		}
+
+		if _resolver.Tracer != nil {
+			for _, constructor := range _resolver.ConstructorsByType[_type] {
+				_resolver.Tracer.enter(constructor)
+			}
+		}
+
+		return values, nil
+	}
+
+	var values []reflect.Value
+
+	constructors := _resolver.Container.findConstructors(_type)
+
+	for _, constructor := range constructors {
+		value, err := _resolver.provide(constructor, _type)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
 	}
+
+	// Values of transient constructors are never shared
+	for _, constructor := range constructors {
+		if constructor.Transient {
+			return values, nil
+		}
+	}
+
+	_resolver.ValuesByType[_type] = values
+	_resolver.ConstructorsByType[_type] = constructors
+
+	return values, nil
 }
 
-func newResolver(container *Container) *resolver {
-	return &resolver{
-		Container: container,
+func (container *Container) checkSingle(_type reflect.Type, count int) error {
+	if count == 0 {
+		// A decorator without a value to decorate depends on itself
+		for _, decorator := range container.allConstructors() {
+			if decorator.Decorator && decorator.ReturnType.AssignableTo(_type) {
+				return dependencyCycle(
+					decorator.ReturnType, []*constructor{decorator},
+					"Self-cycle detected for constructor '%s' taking its own return type '%s' without another constructor to decorate",
+					decorator.Function.Type(), decorator.ReturnType,
+				)
+			}
+		}
 
-		ValuesByType:       make(map[reflect.Type][]reflect.Value),
-		VisitedTypes:       make(map[reflect.Type]bool),
-		ValueByConstructor: make(map[*constructor]reflect.Value),
+		if constructor := container.inactiveVariant(_type); constructor != nil {
+			return missingDependency(
+				_type, "No variant of type '%s' registered for mode '%v', constructor '%s' is registered for mode '%v'",
+				_type, container.mode, constructor.Function.Type(), constructor.Variant,
+			)
+		}
+
+		if container.explicitInterfaces && _type.Kind() == reflect.Interface {
+			for _, constructor := range container.allConstructors() {
+				if !constructor.Decorator && constructor.ReturnType.AssignableTo(_type) {
+					return missingDependency(
+						_type, "No binding defined for interface '%s', constructor '%s' implements it but interfaces must be bound explicitly",
+						_type, constructor.Function.Type(),
+					)
+				}
+			}
+		}
+
+		// Point out structs implementing the interface only by their pointers
+		if candidates := container.pointerReceiverConstructors(_type); _type.Kind() == reflect.Interface && len(candidates) > 0 {
+			return missingDependency(
+				_type, "No constructor defined for type '%s', type '%s' returned by constructor '%s' implements it only with pointer receivers",
+				_type, candidates[0].ReturnType, candidates[0].Function.Type(),
+			)
+		}
+
+		// Point out constructors hiding the requested type behind an interface
+		for _, constructor := range container.allConstructors() {
+			if constructor.ReturnType.Kind() == reflect.Interface && _type.Implements(constructor.ReturnType) {
+				return missingDependency(
+					_type, "No constructor defined for type '%s', constructor '%s' returns an interface",
+					_type, constructor.Function.Type(),
+				)
+			}
+		}
+
+		return missingDependency(_type, "No constructor defined for type '%s'", _type)
 	}
+
+	if count > 1 {
+		return ambiguousDependency(_type, "Ambiguity detected for type '%s'", _type)
+	}
+
+	return nil
 }
 
-func (_resolver *resolver) typeResolved(_type reflect.Type) bool {
-	_, ok := _resolver.ValuesByType[_type]
-	return ok
+// supportedParameter rejects parameter types that look like collections or
+// references of dependencies but cannot be resolved.
+func supportedParameter(param reflect.Type) bool {
+	switch param.Kind() {
+	case reflect.Ptr:
+		return param.Elem().Kind() != reflect.Interface && param.Elem().Kind() != reflect.Slice
+	case reflect.Slice:
+		elem := param.Elem()
+		return elem.Kind() != reflect.Slice && supportedParameter(elem)
+	case reflect.Map:
+		return param.Elem().Kind() != reflect.Interface
+	}
+
+	return true
 }
 
 func innerType(rawType reflect.Type) reflect.Type {
@@ -225,51 +1206,140 @@ func innerType(rawType reflect.Type) reflect.Type {
 	return rawType
 }
 
-func (_resolver *resolver) constructorInvokable(constructor *constructor) bool {
-	for _, rawParam := range constructor.Parameters {
-		param := innerType(rawParam)
+func (_resolver *resolver) invokeConstructor(constructor *constructor, _type reflect.Type) (reflect.Value, error) {
+	var node *TraceNode
 
-		if param.Kind() == reflect.Slice {
-			if len(_resolver.ValuesByType[param]) != len(_resolver.Container.findConstructors(param)) {
-				return false
-			}
+	if _resolver.Tracer != nil {
+		node = _resolver.Tracer.enter(constructor)
+	}
 
-			continue
+	if value, ok := _resolver.constructorInvoked(constructor); ok && !_resolver.transient(constructor) {
+		if _resolver.Placeholder[constructor] {
+			_resolver.Placeholders++
 		}
 
-		if !_resolver.typeResolved(param) {
-			return false
+		_resolver.require(constructor)
+
+		return value, nil
+	}
+
+	if constructor.Deferred && !_resolver.Activating {
+		return reflect.Value{}, fmt.Errorf("Type '%s' of constructor '%s' is deferred and can only be constructed by Activate", constructor.ReturnType, constructor.Function.Type())
+	}
+
+	for i, pending := range _resolver.PendingConstructors {
+		if pending == constructor {
+			dependent := _resolver.PendingConstructors[len(_resolver.PendingConstructors)-1]
+
+			if value, ok, err := _resolver.breakCycle(_resolver.PendingConstructors[i:], _type); ok {
+				return value, err
+			}
+
+			return reflect.Value{}, dependencyCycle(
+				constructor.ReturnType, _resolver.PendingConstructors[i:],
+				"Cycle detected for parameter '%s' of constructor '%s' while resolving type '%s'.",
+				_type, dependent.Function.Type(), constructor.ReturnType,
+			)
 		}
 	}
 
-	return true
-}
+	_resolver.PendingConstructors = append(_resolver.PendingConstructors, constructor)
+	_resolver.PendingDepths = append(_resolver.PendingDepths, 0)
+
+	defer func() {
+		_resolver.PendingConstructors = _resolver.PendingConstructors[:len(_resolver.PendingConstructors)-1]
+		_resolver.PendingDepths = _resolver.PendingDepths[:len(_resolver.PendingDepths)-1]
+		_resolver.require(constructor)
+	}()
+
+	if node != nil {
+		_resolver.Tracer.Pending = append(_resolver.Tracer.Pending, node)
+
+		defer func() {
+			_resolver.Tracer.Pending = _resolver.Tracer.Pending[:len(_resolver.Tracer.Pending)-1]
+		}()
+	}
 
-func (_resolver *resolver) invokeConstructor(constructor *constructor, _type reflect.Type) reflect.Value {
 	var arguments []reflect.Value
 
-	for _, rawParam := range constructor.Parameters {
-		param := innerType(rawParam)
+	placeholders := _resolver.Placeholders
 
-		if rawParam.Kind() == reflect.Slice {
-			paramSliceValue := reflect.MakeSlice(reflect.SliceOf(param), 0, 0)
-			paramSliceValue = reflect.Append(paramSliceValue, _resolver.ValuesByType[param]...)
-			arguments = append(arguments, paramSliceValue)
+	for i, param := range constructor.Parameters {
+		// Decorators receive the value they decorate
+		if constructor.Decorator && param == constructor.ReturnType {
+			arguments = append(arguments, _resolver.DecoratedValues[constructor])
 			continue
 		}
 
-		if len(_resolver.ValuesByType[param]) > 1 {
-			panic(fmt.Sprintf("Ambiguity detected for type '%s'", param))
+		argument, err := _resolver.resolveDependency(constructor.Dependencies[i])
+
+		if err != nil {
+			argument, err = _resolver.resolveDefault(constructor, i, err)
+		}
+
+		if err != nil {
+			_resolver.chainError(err)
+			return reflect.Value{}, err
 		}
 
-		arguments = append(arguments, _resolver.ValuesByType[param][0])
+		arguments = append(arguments, argument)
 	}
 
-	value := constructor.Function.Call(arguments)[0]
-	return value
+	if err := _resolver.Context.Err(); err != nil {
+		return reflect.Value{}, fmt.Errorf("Resolution aborted while constructing type '%s': %w", constructor.ReturnType, err)
+	}
+
+	var value reflect.Value
+
+	if _resolver.DryRun && !_resolver.invokesPure(constructor, placeholders) {
+		value = _resolver.placeholder(constructor.ReturnType)
+		_resolver.Placeholder[constructor] = true
+	} else {
+		results, err := _resolver.call(constructor, arguments)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value = results[0]
+
+		_resolver.countInstance(constructor)
+
+		if constructor.Cleanup && !results[1].IsNil() {
+			_resolver.Cleanups = append(_resolver.Cleanups, newCleanup(constructor.ReturnType, results[1].Interface()))
+		}
+
+		if err := _resolver.assert(constructor, value); err != nil {
+			return reflect.Value{}, err
+		}
+
+		if constructor.Hook.IsValid() && !_resolver.DryRun {
+			constructor.Hook.Call([]reflect.Value{value})
+		}
+	}
+
+	if _, ok := _resolver.ValueByConstructor[constructor]; !ok {
+		_resolver.InvokedConstructors = append(_resolver.InvokedConstructors, constructor)
+	}
+
+	_resolver.ValueByConstructor[constructor] = value
+	_resolver.Depth[constructor] = _resolver.PendingDepths[len(_resolver.PendingDepths)-1]
+
+	if node != nil {
+		_resolver.Tracer.invoked(node)
+	}
+
+	return value, nil
 }
 
-func (_resolver *resolver) constructorInvoked(constructor *constructor, _type reflect.Type) (reflect.Value, bool) {
-	value, ok := _resolver.ValueByConstructor[constructor]
-	return value, ok
+func (_resolver *resolver) constructorInvoked(constructor *constructor) (reflect.Value, bool) {
+	if value, ok := _resolver.ValueByConstructor[constructor]; ok {
+		return value, true
+	}
+
+	if !_resolver.Singletons {
+		return reflect.Value{}, false
+	}
+
+	return _resolver.reuseSingleton(constructor)
 }
@@ -1,6 +1,10 @@
 package injector
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -172,6 +176,130 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+type NamedCodec interface {
+	Encode() string
+}
+
+type NamedJSONCodec struct {
+	indent string
+}
+
+func (codec NamedJSONCodec) Encode() string {
+	return "json" + codec.indent
+}
+
+type NamedXMLCodec struct{}
+
+func (NamedXMLCodec) Encode() string {
+	return "xml"
+}
+
+type NamedCodecApp struct {
+	Codec   NamedCodec
+	Value   NamedJSONCodec
+	Pointer *NamedJSONCodec
+	Other   *NamedJSONCodec
+}
+
+type NamedCodecInterfaceApp struct {
+	Codec NamedCodec
+}
+
+func TestNamedResultInterface(t *testing.T) {
+	container := NewContainer()
+
+	constructions := 0
+
+	container.Register(func() NamedJSONCodec {
+		constructions++
+		return NamedJSONCodec{indent: "  "}
+	})
+
+	app := &NamedCodecApp{}
+	container.Resolve(app)
+
+	if app.Codec == nil || app.Codec.Encode() != "json  " || constructions != 1 {
+		t.Errorf("NamedCodec could not be resolved")
+	}
+
+	if app.Codec != NamedCodec(app.Value) || app.Pointer == nil || *app.Pointer != app.Value {
+		t.Errorf("Interface and concrete requests did not receive the same value")
+	}
+
+	if app.Pointer != app.Other {
+		t.Errorf("Pointer requests did not share the same instance")
+	}
+
+	container.Register(func() NamedXMLCodec { return NamedXMLCodec{} })
+
+	err := container.ResolveContext(context.Background(), &NamedCodecInterfaceApp{})
+
+	if err == nil || !strings.HasPrefix(err.Error(), "Ambiguity detected for type 'injector.NamedCodec'") {
+		t.Errorf("Several codecs did not cause an ambiguity: %v", err)
+	}
+}
+
+type TransitiveInterfaceApp struct {
+	Consumer *TransitiveInterfaceConsumer
+}
+
+type TransitiveInterfaceConsumer struct {
+	ready bool
+}
+
+type TransitiveInterfaceStore interface {
+	Ready() bool
+}
+
+type TransitiveInterfaceDatabase struct {
+	config *TransitiveInterfaceConfig
+	ready  bool
+}
+
+func (database *TransitiveInterfaceDatabase) Ready() bool {
+	return database.ready && database.config.dsn != ""
+}
+
+type TransitiveInterfaceConfig struct {
+	dsn string
+}
+
+func NewTransitiveInterfaceConsumer(store TransitiveInterfaceStore) *TransitiveInterfaceConsumer {
+	// Record the state at construction time to detect half-built stores
+	return &TransitiveInterfaceConsumer{
+		ready: store.Ready(),
+	}
+}
+
+func NewTransitiveInterfaceDatabase(config *TransitiveInterfaceConfig) *TransitiveInterfaceDatabase {
+	database := &TransitiveInterfaceDatabase{
+		config: config,
+	}
+
+	database.ready = true
+
+	return database
+}
+
+func NewTransitiveInterfaceConfig() *TransitiveInterfaceConfig {
+	return &TransitiveInterfaceConfig{
+		dsn: "postgres://",
+	}
+}
+
+func TestTransitiveInterface(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewTransitiveInterfaceConsumer, NewTransitiveInterfaceDatabase, NewTransitiveInterfaceConfig)
+
+	app := &TransitiveInterfaceApp{}
+	container.Resolve(app)
+
+	if !app.Consumer.ready {
+		t.Errorf("Store was not fully built before it was injected")
+	}
+}
+
 type ImplementionApp struct {
 	Foo *ImplementionFoo
 }
@@ -536,3 +664,929 @@ func TestMulti(t *testing.T) {
 		t.Errorf("Foo could not be resolved")
 	}
 }
+
+type InstanceApp struct {
+	Foo *InstanceFoo
+	Bar InstanceBar
+}
+
+type InstanceFoo struct {
+	value string
+}
+
+type InstanceBar interface {
+	Bar() string
+}
+
+type InstanceActualBar struct {
+}
+
+func (bar *InstanceActualBar) Bar() string {
+	return "bar"
+}
+
+func TestInstance(t *testing.T) {
+	container := NewContainer()
+
+	foo := &InstanceFoo{value: "foo"}
+	container.RegisterInstance(foo, &InstanceActualBar{})
+
+	app := &InstanceApp{}
+	container.Resolve(app)
+
+	if app.Foo != foo {
+		t.Errorf("Foo could not be resolved")
+	}
+
+	if app.Bar.Bar() != "bar" {
+		t.Errorf("Bar could not be resolved")
+	}
+}
+
+type InstanceInterfaceApp struct {
+	Bar       InstanceBar
+	ActualBar *InstanceActualBar
+	Bars      []InstanceBar
+}
+
+func TestInstanceInterface(t *testing.T) {
+	var bar InstanceBar = &InstanceActualBar{}
+
+	for _, withConstructor := range []bool{false, true} {
+		container := NewContainer()
+		container.RegisterInstance(bar)
+
+		// A constructor forces the object graph to be walked
+		if withConstructor {
+			container.Register(func() *InstanceFoo { return &InstanceFoo{} })
+		}
+
+		app := &InstanceInterfaceApp{}
+		container.Resolve(app)
+
+		if app.Bar != bar || app.ActualBar != bar {
+			t.Errorf("Interface instance could not be resolved")
+		}
+
+		if len(app.Bars) != 1 || app.Bars[0] != bar {
+			t.Errorf("Interface instance could not be resolved as slice")
+		}
+	}
+}
+
+func newInstanceContainer() *Container {
+	container := NewContainer()
+	container.RegisterInstance(&InstanceFoo{value: "foo"}, &InstanceActualBar{})
+	return container
+}
+
+func BenchmarkResolveInstances(b *testing.B) {
+	container := newInstanceContainer()
+
+	for i := 0; i < b.N; i++ {
+		container.Resolve(&InstanceApp{})
+	}
+}
+
+func BenchmarkResolveInstancesGraph(b *testing.B) {
+	container := newInstanceContainer()
+
+	for i := 0; i < b.N; i++ {
+		container.resolveGraph(newResolver(context.Background(), container), []interface{}{&InstanceApp{}})
+	}
+}
+
+type OverrideApp struct {
+	Foo *OverrideFoo
+}
+
+type OverrideFoo struct {
+	bar OverrideBar
+}
+
+type OverrideBar interface {
+	Bar() string
+}
+
+type OverrideActualBar struct {
+}
+
+func (bar *OverrideActualBar) Bar() string {
+	return "bar"
+}
+
+type OverrideFakeBar struct {
+}
+
+func (bar *OverrideFakeBar) Bar() string {
+	return "fake_bar"
+}
+
+func NewOverrideFoo(bar OverrideBar) *OverrideFoo {
+	return &OverrideFoo{
+		bar: bar,
+	}
+}
+
+func TestOverride(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(NewOverrideFoo, func() *OverrideActualBar {
+		constructed++
+		return &OverrideActualBar{}
+	})
+
+	app := &OverrideApp{}
+	container.ResolveWith(app, map[reflect.Type]interface{}{
+		reflect.TypeOf((*OverrideBar)(nil)).Elem(): &OverrideFakeBar{},
+	})
+
+	if app.Foo.bar.Bar() != "fake_bar" || constructed != 0 {
+		t.Errorf("Bar could not be overridden")
+	}
+
+	app = &OverrideApp{}
+	container.Resolve(app)
+
+	if app.Foo.bar.Bar() != "bar" || constructed != 1 {
+		t.Errorf("Override was not limited to a single resolution")
+	}
+}
+
+type EmptySliceApp struct {
+	Foo *EmptySliceFoo
+}
+
+type EmptySliceFoo struct {
+	bars []EmptySliceBar
+}
+
+type EmptySliceBar interface {
+	Bar() string
+}
+
+func NewEmptySliceFoo(bars []EmptySliceBar) *EmptySliceFoo {
+	return &EmptySliceFoo{
+		bars: bars,
+	}
+}
+
+func TestEmptySlice(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewEmptySliceFoo)
+
+	app := &EmptySliceApp{}
+	container.Resolve(app)
+
+	if app.Foo == nil || len(app.Foo.bars) != 0 {
+		t.Errorf("Foo could not be resolved")
+	}
+}
+
+type UnsupportedBar interface {
+	Bar() string
+}
+
+func TestUnsupportedParameter(t *testing.T) {
+	constructors := []interface{}{
+		func(bars []*UnsupportedBar) *Foo { return nil },
+		func(bars [][]UnsupportedBar) *Foo { return nil },
+		func(bars map[string]UnsupportedBar) *Foo { return nil },
+		func(bars *[]UnsupportedBar) *Foo { return nil },
+		func(bar *UnsupportedBar) *Foo { return nil },
+	}
+
+	for _, constructor := range constructors {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Unsupported parameter of '%T' was not detected", constructor)
+				}
+			}()
+
+			NewContainer().Register(constructor)
+		}()
+	}
+}
+
+type ChannelApp struct {
+	Publisher  *ChannelPublisher
+	Subscriber *ChannelSubscriber
+}
+
+type ChannelEvent struct {
+	value string
+}
+
+type ChannelPublisher struct {
+	events chan<- ChannelEvent
+}
+
+type ChannelSubscriber struct {
+	events <-chan ChannelEvent
+}
+
+func NewChannelPublisher(events chan<- ChannelEvent) *ChannelPublisher {
+	return &ChannelPublisher{
+		events: events,
+	}
+}
+
+func NewChannelSubscriber(events <-chan ChannelEvent) *ChannelSubscriber {
+	return &ChannelSubscriber{
+		events: events,
+	}
+}
+
+func TestChannel(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterInstance(make(chan ChannelEvent, 1))
+	container.Register(NewChannelPublisher, NewChannelSubscriber)
+
+	app := &ChannelApp{}
+	container.Resolve(app)
+
+	app.Publisher.events <- ChannelEvent{value: "event"}
+
+	select {
+	case event := <-app.Subscriber.events:
+		if event.value != "event" {
+			t.Errorf("Subscriber received unexpected event")
+		}
+	default:
+		t.Errorf("Publisher and Subscriber do not share a channel")
+	}
+}
+
+type ContextApp struct {
+	Foo *ContextFoo
+}
+
+type ContextFoo struct {
+	bar *ContextBar
+}
+
+type ContextBar struct {
+	baz *ContextBaz
+}
+
+type ContextBaz struct {
+}
+
+func TestContext(t *testing.T) {
+	container := NewContainer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var constructed []string
+
+	container.Register(
+		func(bar *ContextBar) *ContextFoo {
+			constructed = append(constructed, "foo")
+			return &ContextFoo{bar: bar}
+		},
+		func(baz *ContextBaz) *ContextBar {
+			constructed = append(constructed, "bar")
+			cancel()
+			return &ContextBar{baz: baz}
+		},
+		func() *ContextBaz {
+			constructed = append(constructed, "baz")
+			return &ContextBaz{}
+		},
+	)
+
+	app := &ContextApp{}
+	err := container.ResolveContext(ctx, app)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Cancellation was not detected")
+	}
+
+	if len(constructed) != 2 || app.Foo != nil {
+		t.Errorf("Resolution was not aborted")
+	}
+}
+
+func TestContextError(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewMissingFoo)
+
+	if err := container.ResolveContext(context.Background(), &MissingApp{}); err == nil {
+		t.Errorf("Missing dependency was not detected")
+	}
+}
+
+type InterfaceReturnApp struct {
+	Foo *InterfaceReturnFoo
+}
+
+type InterfaceReturnFoo struct {
+	logger InterfaceReturnLogger
+}
+
+type InterfaceReturnLogger interface {
+	Log() string
+}
+
+type InterfaceReturnFileLogger struct {
+}
+
+func (logger *InterfaceReturnFileLogger) Log() string {
+	return "file"
+}
+
+func NewInterfaceReturnFoo(logger InterfaceReturnLogger) *InterfaceReturnFoo {
+	return &InterfaceReturnFoo{
+		logger: logger,
+	}
+}
+
+func NewInterfaceReturnLogger() InterfaceReturnLogger {
+	return &InterfaceReturnFileLogger{}
+}
+
+func TestInterfaceReturn(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewInterfaceReturnFoo, NewInterfaceReturnLogger)
+
+	app := &InterfaceReturnApp{}
+	container.Resolve(app)
+
+	if app.Foo.logger.Log() != "file" {
+		t.Errorf("Foo could not be resolved")
+	}
+}
+
+type InterfaceReturnConsoleLogger struct {
+	prefix string
+}
+
+func (logger *InterfaceReturnConsoleLogger) Log() string {
+	return "console"
+}
+
+func TestInterfaceReturnConcrete(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() InterfaceReturnLogger {
+		return &InterfaceReturnConsoleLogger{}
+	})
+
+	app := &struct {
+		Logger *InterfaceReturnFileLogger
+	}{}
+
+	err := container.ResolveContext(context.Background(), app)
+
+	if err == nil || !strings.Contains(err.Error(), "returns an interface") {
+		t.Errorf("Concrete request of interface constructor was not explained: %v", err)
+	}
+}
+
+func TestInterfaceReturnAssertion(t *testing.T) {
+	container := NewContainer()
+
+	logger := &InterfaceReturnConsoleLogger{prefix: "app"}
+
+	container.Register(func() InterfaceReturnLogger {
+		return logger
+	})
+
+	app := &struct {
+		Logger    *InterfaceReturnConsoleLogger
+		Interface InterfaceReturnLogger
+	}{}
+
+	container.Resolve(app)
+
+	if app.Logger != logger || app.Interface != logger {
+		t.Errorf("Concrete value of interface constructor could not be resolved")
+	}
+}
+
+func TestInterfaceReturnAmbiguous(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewInterfaceReturnFoo, NewInterfaceReturnLogger, func() InterfaceReturnLogger {
+		return &InterfaceReturnFileLogger{}
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Ambiguous dependency was not detected")
+		}
+	}()
+
+	container.Resolve(&InterfaceReturnApp{})
+}
+
+type OrderApp struct {
+	Baz   *OrderBaz
+	Qux   OrderQuxInterface
+	Count int
+}
+
+type OrderFoo struct {
+	value string
+}
+
+type OrderBar struct {
+	foo *OrderFoo
+}
+
+type OrderBaz struct {
+	foo *OrderFoo
+	bar *OrderBar
+	qux OrderQuxInterface
+}
+
+type OrderQuxInterface interface {
+	Qux() *OrderBar
+}
+
+type OrderQux struct {
+	bar *OrderBar
+}
+
+func (qux *OrderQux) Qux() *OrderBar {
+	return qux.bar
+}
+
+func NewOrderFoo() *OrderFoo {
+	return &OrderFoo{
+		value: "foo",
+	}
+}
+
+func NewOrderBar(foo *OrderFoo) *OrderBar {
+	return &OrderBar{
+		foo: foo,
+	}
+}
+
+func NewOrderBaz(foo *OrderFoo, bar *OrderBar, qux OrderQuxInterface) *OrderBaz {
+	return &OrderBaz{
+		foo: foo,
+		bar: bar,
+		qux: qux,
+	}
+}
+
+func NewOrderQux(bar *OrderBar) *OrderQux {
+	return &OrderQux{
+		bar: bar,
+	}
+}
+
+func NewOrderCount(foo *OrderFoo, qux OrderQuxInterface) int {
+	return len(foo.value)
+}
+
+func permutations(values []interface{}) [][]interface{} {
+	if len(values) <= 1 {
+		return [][]interface{}{values}
+	}
+
+	var result [][]interface{}
+
+	for i := range values {
+		rest := append(append([]interface{}{}, values[:i]...), values[i+1:]...)
+
+		for _, permutation := range permutations(rest) {
+			result = append(result, append([]interface{}{values[i]}, permutation...))
+		}
+	}
+
+	return result
+}
+
+func TestRegistrationOrder(t *testing.T) {
+	constructors := []interface{}{NewOrderFoo, NewOrderBar, NewOrderBaz, NewOrderQux, NewOrderCount}
+
+	for _, permutation := range permutations(constructors) {
+		container := NewContainer()
+
+		container.Register(permutation...)
+
+		app := &OrderApp{}
+		container.Resolve(app)
+
+		baz := app.Baz
+
+		if baz.foo.value != "foo" || baz.bar.foo != baz.foo || baz.qux != app.Qux || baz.qux.Qux() != baz.bar || app.Count != 3 {
+			t.Errorf("Registration order %v changed the resolved graph", permutation)
+		}
+	}
+}
+
+type ConsistencyApp struct {
+	Foo *ConsistencyFoo
+}
+
+type ConsistencyFoo struct {
+	value string
+}
+
+func TestResolverInconsistency(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *ConsistencyFoo {
+		return &ConsistencyFoo{}
+	})
+
+	fooType := reflect.TypeOf(&ConsistencyFoo{})
+
+	// Stub the resolver with states it never produces itself
+	states := map[string][]reflect.Value{
+		"no value":      {},
+		"invalid value": {{}},
+	}
+
+	for state, values := range states {
+		resolver := newResolver(context.Background(), container)
+		resolver.ValuesByType[fooType] = values
+
+		app := &ConsistencyApp{}
+
+		err := container.resolveGraph(resolver, []interface{}{app})
+
+		if err == nil || !strings.HasPrefix(err.Error(), "Internal error") {
+			t.Errorf("Resolver with %s was not reported: %v", state, err)
+		}
+
+		if app.Foo != nil {
+			t.Errorf("Root was changed despite an inconsistent resolver")
+		}
+	}
+}
+
+type ResolveAllServer struct {
+	Pool *ResolveAllPool
+}
+
+type ResolveAllWorkers struct {
+	Pool *ResolveAllPool
+}
+
+type ResolveAllPool struct {
+	size int
+}
+
+func TestResolveAll(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(func() *ResolveAllPool {
+		constructed++
+		return &ResolveAllPool{size: 4}
+	})
+
+	server := &ResolveAllServer{}
+	workers := &ResolveAllWorkers{}
+
+	container.ResolveAll(server, workers)
+
+	if server.Pool == nil || server.Pool != workers.Pool {
+		t.Errorf("Pool was not shared between roots")
+	}
+
+	if constructed != 1 {
+		t.Errorf("Pool was constructed %d times", constructed)
+	}
+}
+
+type SeedApp struct {
+	Service *SeedService
+}
+
+type SeedService struct {
+	repo   *SeedRepo
+	reader SeedReader
+}
+
+type SeedReader interface {
+	Read() string
+}
+
+type SeedRepo struct {
+	db *SeedDB
+}
+
+func (repo *SeedRepo) Read() string {
+	return "repo"
+}
+
+type SeedDB struct {
+	dsn string
+}
+
+func TestResolveSeeded(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(func(repo *SeedRepo, reader SeedReader) *SeedService {
+		return &SeedService{repo: repo, reader: reader}
+	}, func(db *SeedDB) *SeedRepo {
+		constructed++
+		return &SeedRepo{db: db}
+	}, func() *SeedDB {
+		constructed++
+		return &SeedDB{}
+	})
+
+	repo := &SeedRepo{}
+
+	app := &SeedApp{}
+	container.ResolveSeeded(app, map[reflect.Type]interface{}{
+		reflect.TypeOf(repo): repo,
+	})
+
+	if app.Service.repo != repo || app.Service.reader != repo {
+		t.Errorf("Seed was not injected")
+	}
+
+	if constructed != 0 {
+		t.Errorf("Seeded subtree was constructed")
+	}
+}
+
+type SeedReadersApp struct {
+	Readers []SeedReader
+}
+
+type SeedCacheReader struct {
+	name string
+}
+
+func (reader *SeedCacheReader) Read() string {
+	return reader.name
+}
+
+func TestResolveSeededSlice(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func(db *SeedDB) *SeedRepo {
+		return &SeedRepo{db: db}
+	}, func() *SeedDB {
+		return &SeedDB{}
+	})
+
+	cache := &SeedCacheReader{name: "cache"}
+
+	app := &SeedReadersApp{}
+	container.ResolveSeeded(app, map[reflect.Type]interface{}{
+		reflect.TypeOf(cache): cache,
+	})
+
+	if len(app.Readers) != 2 || app.Readers[0].Read() != "repo" || app.Readers[1] != cache {
+		t.Errorf("Seed was not collected into the slice: %v", app.Readers)
+	}
+}
+
+type ReuseCoreApp struct {
+	Repo   *SeedRepo
+	Reader SeedReader
+}
+
+func TestResolveReusing(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(func(repo *SeedRepo, reader SeedReader) *SeedService {
+		return &SeedService{repo: repo, reader: reader}
+	}, func(db *SeedDB) *SeedRepo {
+		constructed++
+		return &SeedRepo{db: db}
+	}, func() *SeedDB {
+		return &SeedDB{dsn: "postgres://"}
+	})
+
+	core := &ReuseCoreApp{}
+	container.Resolve(core)
+
+	app := &SeedApp{}
+	container.ResolveReusing(app, core)
+
+	if app.Service.repo != core.Repo || app.Service.reader != core.Reader {
+		t.Errorf("Values of existing root were not reused")
+	}
+
+	if constructed != 1 {
+		t.Errorf("Reused value was constructed again")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Conflicting values of existing root were not detected")
+		}
+	}()
+
+	container.ResolveReusing(&SeedApp{}, &ReuseCoreApp{Repo: &SeedRepo{}, Reader: &SeedRepo{}})
+}
+
+type EmbeddedApp struct {
+	EmbeddedGreeter
+}
+
+type EmbeddedGreeter interface {
+	Greet() string
+}
+
+type EmbeddedEnglishGreeter struct {
+	greeting string
+}
+
+func (greeter *EmbeddedEnglishGreeter) Greet() string {
+	return greeter.greeting
+}
+
+func TestEmbeddedInterface(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *EmbeddedEnglishGreeter {
+		return &EmbeddedEnglishGreeter{greeting: "hello"}
+	})
+
+	app := &EmbeddedApp{}
+	container.Resolve(app)
+
+	if app.EmbeddedGreeter == nil || app.Greet() != "hello" {
+		t.Errorf("Embedded greeter could not be resolved")
+	}
+}
+
+type embeddedUnexportedApp struct {
+	embeddedGreeter
+}
+
+type embeddedGreeter interface {
+	Greet() string
+}
+
+func TestEmbeddedUnexportedInterface(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *EmbeddedEnglishGreeter {
+		return &EmbeddedEnglishGreeter{greeting: "hello"}
+	})
+
+	err := container.ResolveContext(context.Background(), &embeddedUnexportedApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "unexported") {
+		t.Errorf("Unexported embedded field was not reported: %v", err)
+	}
+}
+
+type UnexportedApp struct {
+	Greeter UnexportedGreeter
+}
+
+type UnexportedGreeter interface {
+	Greet() string
+}
+
+type unexportedGreeter struct {
+	greeting string
+}
+
+func (greeter *unexportedGreeter) Greet() string {
+	return greeter.greeting
+}
+
+func TestUnexportedImplementation(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterInstance(&unexportedGreeter{greeting: "hello"})
+
+	app := &UnexportedApp{}
+	container.Resolve(app)
+
+	if app.Greeter.Greet() != "hello" {
+		t.Errorf("Greeter could not be resolved")
+	}
+
+	if _, ok := container.ProviderOf(app.Greeter); !ok {
+		t.Errorf("Provider of unexported implementation could not be found")
+	}
+
+	container.RegisterInstance(&unexportedGreeter{greeting: "hi"})
+
+	err := container.ResolveContext(context.Background(), &UnexportedApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "injector.UnexportedGreeter") {
+		t.Errorf("Ambiguity of unexported implementations was not reported readably: %v", err)
+	}
+}
+
+type ValueTypeUserID string
+
+type ValueTypeRequestID [16]byte
+
+type ValueTypeApp struct {
+	Session *ValueTypeSession
+	UserID  ValueTypeUserID
+}
+
+type ValueTypeSession struct {
+	started   time.Time
+	userID    ValueTypeUserID
+	requestID ValueTypeRequestID
+}
+
+func NewValueTypeSession(started time.Time, userID ValueTypeUserID, requestID ValueTypeRequestID) *ValueTypeSession {
+	return &ValueTypeSession{
+		started:   started,
+		userID:    userID,
+		requestID: requestID,
+	}
+}
+
+func TestValueTypeInstances(t *testing.T) {
+	container := NewContainer()
+
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	container.Register(NewValueTypeSession)
+	container.RegisterInstance(started, ValueTypeUserID("user"), ValueTypeRequestID{1, 2, 3})
+
+	app := &ValueTypeApp{}
+	container.Resolve(app)
+
+	session := app.Session
+
+	if !session.started.Equal(started) || session.userID != "user" || session.requestID != (ValueTypeRequestID{1, 2, 3}) {
+		t.Errorf("Session could not be resolved")
+	}
+
+	if app.UserID != "user" {
+		t.Errorf("UserID could not be resolved")
+	}
+
+	container = NewContainer()
+	container.RegisterInstance(ValueTypeUserID("user"), "other")
+
+	err := container.ResolveContext(context.Background(), &struct{ UserID ValueTypeUserID }{})
+
+	if err != nil {
+		t.Errorf("UserID could not be told apart from its underlying type: %s", err)
+	}
+}
+
+type AliasConfig struct {
+	port int
+}
+
+type AliasSettings = AliasConfig
+
+type DefinedSettings AliasConfig
+
+type DefinedPort int
+
+type AliasStore = ConcreteStore
+
+type DefinedStore ConcreteStore
+
+func TestAliasTypes(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		func() *AliasConfig { return &AliasConfig{port: 8080} },
+		func() int { return 8080 },
+		NewConcreteMemoryStore,
+	)
+
+	app := &struct {
+		Settings *AliasSettings
+		Store    AliasStore
+		Defined  DefinedStore
+	}{}
+
+	container.Resolve(app)
+
+	if app.Settings == nil || app.Settings.port != 8080 {
+		t.Errorf("Alias of config could not be resolved")
+	}
+
+	if app.Store.Name() != "memory" || app.Defined.Name() != "memory" {
+		t.Errorf("Alias or defined interface could not be resolved")
+	}
+
+	if err := container.ResolveContext(context.Background(), &struct{ Settings *DefinedSettings }{}); err == nil {
+		t.Errorf("Defined struct type was resolved from its underlying type")
+	}
+
+	if err := container.ResolveContext(context.Background(), &struct{ Port DefinedPort }{}); err == nil {
+		t.Errorf("Defined int type was resolved from its underlying type")
+	}
+}
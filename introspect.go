@@ -0,0 +1,286 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// remember keeps the values of the last resolution for introspection along
+// with the constructors whose values were reused from earlier resolutions.
+func (container *Container) remember(constructors []*constructor, valueByConstructor map[*constructor]reflect.Value, reused map[*constructor]bool, warnings []error) {
+	container.resolvedConstructors = constructors
+	container.resolvedReused = reused
+	container.resolvedWarnings = warnings
+	container.resolvedValues = valueByConstructor
+	container.resolvedProviders = make(map[interface{}]*constructor)
+
+	for _, constructor := range constructors {
+		key := identityKey(valueByConstructor[constructor])
+
+		if _, ok := container.resolvedProviders[key]; key != nil && !ok {
+			container.resolvedProviders[key] = constructor
+		}
+	}
+}
+
+// ProviderOf returns the signature of the constructor that produced the
+// instance during the last resolution. Only instances that are pointers,
+// maps, channels or functions can be traced back to their constructor.
+//
+//   signature, ok := container.ProviderOf(app.Server.DB) // "func(*Config) *DB"
+func (container *Container) ProviderOf(instance interface{}) (string, bool) {
+	key := identityKey(reflect.ValueOf(instance))
+
+	if key == nil {
+		return "", false
+	}
+
+	constructor, ok := container.resolvedProviders[key]
+
+	if !ok {
+		return "", false
+	}
+
+	return constructor.Function.Type().String(), true
+}
+
+// WalkResolved calls the function for every value constructed by the last
+// resolution in the order the values were constructed, including the
+// singletons it reused from earlier resolutions, see Reused. The type is the
+// return type of the constructor that produced the value.
+//
+//   container.WalkResolved(func(_type reflect.Type, value interface{}) {
+//     if checker, ok := value.(HealthChecker); ok {
+//       checkers = append(checkers, checker)
+//     }
+//   })
+func (container *Container) WalkResolved(walk func(_type reflect.Type, value interface{})) {
+	for _, constructor := range container.resolvedConstructors {
+		walk(constructor.ReturnType, container.resolvedValues[constructor].Interface())
+	}
+}
+
+// Initialization describes a constructor invoked by a resolution.
+type Initialization struct {
+	Type        reflect.Type
+	Constructor string
+}
+
+// Initialized returns the constructors invoked by the last resolution in the
+// order they were invoked, dependencies before their dependents. Constructors
+// whose values were reused from earlier resolutions are listed in the same
+// place as if they were invoked again, Reused tells them apart. The order is
+// the same for every resolution of the same registrations.
+//
+//   for _, initialization := range container.Initialized() {
+//     log.Printf("Initialized %s", initialization.Type)
+//   }
+func (container *Container) Initialized() []Initialization {
+	return container.initializations(func(*constructor) bool { return true })
+}
+
+// Reused returns the constructors listed by Initialized whose values the last
+// resolution reused from earlier resolutions instead of invoking them, in the
+// same order.
+//
+//   for _, initialization := range container.Reused() {
+//     log.Printf("Reused %s", initialization.Type)
+//   }
+func (container *Container) Reused() []Initialization {
+	return container.initializations(func(constructor *constructor) bool {
+		return container.resolvedReused[constructor]
+	})
+}
+
+func (container *Container) initializations(include func(*constructor) bool) []Initialization {
+	var initializations []Initialization
+
+	for _, constructor := range container.resolvedConstructors {
+		if !include(constructor) {
+			continue
+		}
+
+		initializations = append(initializations, Initialization{
+			Type:        constructor.ReturnType,
+			Constructor: constructor.Function.Type().String(),
+		})
+	}
+
+	return initializations
+}
+
+// InterfaceMatch pairs an interface requested by a constructor or a root with
+// a constructor whose return type implements it.
+type InterfaceMatch struct {
+	Interface   reflect.Type
+	Type        reflect.Type
+	Constructor string
+}
+
+// InterfaceMatches lists every interface requested by a registered
+// constructor or a field of one of the roots together with the constructors
+// that implicitly satisfy it, to audit accidental matches. Constructors
+// returning the interface itself are not listed.
+//
+//   for _, match := range container.InterfaceMatches(&App{}) {
+//     log.Printf("%s is injected for %s", match.Type, match.Interface)
+//   }
+func (container *Container) InterfaceMatches(roots ...interface{}) []InterfaceMatch {
+	var dependencies []*dependency
+
+	for _, constructor := range container.allConstructors() {
+		dependencies = append(dependencies, constructor.Dependencies...)
+	}
+
+	for _, root := range roots {
+		rootType := reflect.TypeOf(root).Elem()
+
+		for i := 0; i < rootType.NumField(); i++ {
+			if dependency, err := newFieldDependency(rootType.Field(i)); err == nil {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	var matches []InterfaceMatch
+
+	listed := make(map[reflect.Type]bool)
+
+	for _, dependency := range dependencies {
+		_type := innerType(dependency.Type)
+
+		if _type.Kind() != reflect.Interface || dependency.tagged() || listed[_type] {
+			continue
+		}
+
+		listed[_type] = true
+
+		for _, constructor := range container.findConstructors(_type) {
+			if constructor.ReturnType == _type {
+				continue
+			}
+
+			matches = append(matches, InterfaceMatch{
+				Interface:   _type,
+				Type:        constructor.ReturnType,
+				Constructor: constructor.Function.Type().String(),
+			})
+		}
+	}
+
+	return matches
+}
+
+// Registration describes a constructor or instance known to a container.
+type Registration struct {
+	Type      reflect.Type
+	Name      string
+	Groups    []string
+	Tags      map[string]string
+	Instance  bool
+	Decorator bool
+}
+
+// Registrations returns all registrations visible to the container, starting
+// with those of the root container, in registration order.
+func (container *Container) Registrations() []Registration {
+	var registrations []Registration
+
+	for _, constructor := range container.allConstructors() {
+		registrations = append(registrations, constructor.registration())
+	}
+
+	return registrations
+}
+
+func (constructor *constructor) registration() Registration {
+	tags := make(map[string]string)
+
+	for key, value := range constructor.Tags {
+		tags[key] = value
+	}
+
+	return Registration{
+		Type:      constructor.ReturnType,
+		Name:      constructor.Name,
+		Groups:    append([]string(nil), constructor.Groups...),
+		Tags:      tags,
+		Instance:  constructor.Instance.IsValid(),
+		Decorator: constructor.Decorator,
+	}
+}
+
+// Diagnostics is a read-only snapshot of the registrations of the container
+// that resolves it. Inject it to introspect the wiring without holding on to
+// the container.
+//
+//   func NewAdminHandler(diagnostics *injector.Diagnostics) *AdminHandler {…}
+type Diagnostics struct {
+	registrations []Registration
+}
+
+func (diagnostics *Diagnostics) synthesize(_resolver *resolver) error {
+	diagnostics.registrations = _resolver.Container.Registrations()
+	return nil
+}
+
+// Count returns the number of registrations.
+func (diagnostics *Diagnostics) Count() int {
+	return len(diagnostics.registrations)
+}
+
+// Registrations returns a copy of the registrations.
+func (diagnostics *Diagnostics) Registrations() []Registration {
+	return append([]Registration(nil), diagnostics.registrations...)
+}
+
+// Names returns the names of all named registrations in registration order.
+func (diagnostics *Diagnostics) Names() []string {
+	var names []string
+
+	for _, registration := range diagnostics.registrations {
+		if registration.Name != "" {
+			names = append(names, registration.Name)
+		}
+	}
+
+	return names
+}
+
+// InstanceCounts returns how many values of each type the constructors of the
+// container created across all of its resolutions, keyed by the return type of
+// the constructors. Transient and keyed types may have more than one instance,
+// registered instances and dry runs are not counted.
+//
+//   for _type, count := range container.InstanceCounts() {
+//     fmt.Printf("%s: %d\n", _type, count)
+//   }
+func (container *Container) InstanceCounts() map[reflect.Type]int {
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	counts := make(map[reflect.Type]int, len(container.instanceCounts))
+
+	for _type, count := range container.instanceCounts {
+		counts[_type] = count
+	}
+
+	return counts
+}
+
+// countInstance counts a value created by the constructor.
+func (_resolver *resolver) countInstance(constructor *constructor) {
+	if _resolver.DryRun || constructor.Instance.IsValid() {
+		return
+	}
+
+	container := _resolver.Container
+
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	if container.instanceCounts == nil {
+		container.instanceCounts = make(map[reflect.Type]int)
+	}
+
+	container.instanceCounts[constructor.ReturnType]++
+}
@@ -0,0 +1,259 @@
+package injector
+
+import (
+	"reflect"
+	"testing"
+)
+
+type WalkApp struct {
+	Server *WalkServer
+}
+
+type WalkHealthChecker interface {
+	Healthy() bool
+}
+
+type WalkServer struct {
+	database *WalkDatabase
+	cache    *WalkCache
+}
+
+type WalkDatabase struct {
+}
+
+func (database *WalkDatabase) Healthy() bool {
+	return true
+}
+
+type WalkCache struct {
+	database *WalkDatabase
+}
+
+func (cache *WalkCache) Healthy() bool {
+	return true
+}
+
+func NewWalkServer(database *WalkDatabase, cache *WalkCache) *WalkServer {
+	return &WalkServer{
+		database: database,
+		cache:    cache,
+	}
+}
+
+func NewWalkDatabase() *WalkDatabase {
+	return &WalkDatabase{}
+}
+
+func NewWalkCache(database *WalkDatabase) *WalkCache {
+	return &WalkCache{
+		database: database,
+	}
+}
+
+func TestWalkResolved(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewWalkServer, NewWalkCache, NewWalkDatabase)
+
+	app := &WalkApp{}
+	container.Resolve(app)
+
+	var types []reflect.Type
+	var checkers []WalkHealthChecker
+
+	container.WalkResolved(func(_type reflect.Type, value interface{}) {
+		types = append(types, _type)
+
+		if checker, ok := value.(WalkHealthChecker); ok {
+			checkers = append(checkers, checker)
+		}
+	})
+
+	expected := []reflect.Type{
+		reflect.TypeOf(&WalkDatabase{}),
+		reflect.TypeOf(&WalkCache{}),
+		reflect.TypeOf(&WalkServer{}),
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Unexpected walk order %v", types)
+	}
+
+	if len(checkers) != 2 || checkers[0] != app.Server.database || checkers[1] != app.Server.cache {
+		t.Errorf("Health checkers could not be collected")
+	}
+}
+
+type DiagnosticsApp struct {
+	Admin *DiagnosticsAdmin
+}
+
+type DiagnosticsAdmin struct {
+	diagnostics *Diagnostics
+}
+
+func NewDiagnosticsAdmin(diagnostics *Diagnostics) *DiagnosticsAdmin {
+	return &DiagnosticsAdmin{
+		diagnostics: diagnostics,
+	}
+}
+
+func TestDiagnosticsInjection(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewDiagnosticsAdmin, NewWalkDatabase)
+	container.RegisterNamed("cache", NewWalkCache)
+
+	app := &DiagnosticsApp{}
+	container.Resolve(app)
+
+	diagnostics := app.Admin.diagnostics
+
+	if diagnostics.Count() != 3 || diagnostics.Count() != len(container.Registrations()) {
+		t.Errorf("Unexpected registration count %d", diagnostics.Count())
+	}
+
+	if names := diagnostics.Names(); len(names) != 1 || names[0] != "cache" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	registrations := diagnostics.Registrations()
+	registrations[0].Name = "changed"
+
+	if diagnostics.Registrations()[0].Name != "" {
+		t.Errorf("Diagnostics could be modified")
+	}
+}
+
+func TestProviderOf(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewWalkServer, NewWalkCache, NewWalkDatabase)
+
+	app := &WalkApp{}
+	container.Resolve(app)
+
+	signature, ok := container.ProviderOf(app.Server.cache)
+
+	if !ok || signature != "func(*injector.WalkDatabase) *injector.WalkCache" {
+		t.Errorf("Provider of cache could not be found: %s", signature)
+	}
+
+	if _, ok := container.ProviderOf(&WalkCache{}); ok {
+		t.Errorf("Provider of unresolved instance was found")
+	}
+}
+
+func TestInitialized(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewWalkServer, NewWalkCache, NewWalkDatabase)
+
+	container.Resolve(&WalkApp{})
+
+	expected := []Initialization{
+		{Type: reflect.TypeOf(&WalkDatabase{}), Constructor: "func() *injector.WalkDatabase"},
+		{Type: reflect.TypeOf(&WalkCache{}), Constructor: "func(*injector.WalkDatabase) *injector.WalkCache"},
+		{Type: reflect.TypeOf(&WalkServer{}), Constructor: "func(*injector.WalkDatabase, *injector.WalkCache) *injector.WalkServer"},
+	}
+
+	if initialized := container.Initialized(); !reflect.DeepEqual(initialized, expected) {
+		t.Errorf("Unexpected init order %v", initialized)
+	}
+
+	container.Resolve(&WalkApp{})
+
+	if initialized := container.Initialized(); !reflect.DeepEqual(initialized, expected) {
+		t.Errorf("Init order changed between resolutions %v", initialized)
+	}
+}
+
+func TestReused(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewWalkServer, NewWalkCache, NewWalkDatabase)
+
+	container.Resolve(&WalkApp{})
+
+	if reused := container.Reused(); len(reused) != 0 {
+		t.Errorf("First resolution reused %v", reused)
+	}
+
+	container.Resolve(&WalkApp{})
+
+	if reused := container.Reused(); !reflect.DeepEqual(reused, container.Initialized()) || len(reused) != 3 {
+		t.Errorf("Unexpected reused constructors %v", reused)
+	}
+
+	container.Override(NewWalkDatabase)
+	container.Resolve(&WalkApp{})
+
+	if reused := container.Reused(); len(reused) != 0 {
+		t.Errorf("Resolution after an override reused %v", reused)
+	}
+}
+
+type InterfaceMatchApp struct {
+	Checker WalkHealthChecker
+}
+
+func TestInterfaceMatches(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewWalkCache, NewWalkDatabase)
+
+	matches := container.InterfaceMatches(&InterfaceMatchApp{})
+
+	checkerType := reflect.TypeOf((*WalkHealthChecker)(nil)).Elem()
+
+	expected := []InterfaceMatch{
+		{Interface: checkerType, Type: reflect.TypeOf(&WalkCache{}), Constructor: "func(*injector.WalkDatabase) *injector.WalkCache"},
+		{Interface: checkerType, Type: reflect.TypeOf(&WalkDatabase{}), Constructor: "func() *injector.WalkDatabase"},
+	}
+
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Unexpected interface matches %v", matches)
+	}
+}
+
+type InstanceCountWorker struct {
+	config *InstanceCountConfig
+}
+
+type InstanceCountConfig struct{}
+
+type InstanceCountName string
+
+type InstanceCountApp struct {
+	First  *InstanceCountWorker
+	Second *InstanceCountWorker
+	Name   InstanceCountName
+}
+
+func TestInstanceCounts(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterTransient(func(config *InstanceCountConfig) *InstanceCountWorker {
+		return &InstanceCountWorker{config: config}
+	})
+
+	container.Register(func() *InstanceCountConfig { return &InstanceCountConfig{} })
+	container.RegisterInstance(InstanceCountName("app"))
+
+	container.Resolve(&InstanceCountApp{})
+	container.Resolve(&InstanceCountApp{})
+
+	counts := container.InstanceCounts()
+
+	if count := counts[reflect.TypeOf(&InstanceCountWorker{})]; count != 4 {
+		t.Errorf("Transient worker was counted %d times", count)
+	}
+
+	if count := counts[reflect.TypeOf(&InstanceCountConfig{})]; count != 1 {
+		t.Errorf("Shared config was counted %d times", count)
+	}
+
+	if _, ok := counts[reflect.TypeOf(InstanceCountName(""))]; ok {
+		t.Errorf("Registered instance was counted")
+	}
+}
@@ -0,0 +1,144 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Invoke calls the function with its parameters resolved like the parameters
+// of a constructor. The function may return an error which is returned by
+// Invoke.
+//
+//   err := container.Invoke(func(server *Server) error {
+//     return server.ListenAndServe()
+//   })
+//
+// Singletons constructed by earlier resolutions of the container are shared,
+// every other value is constructed for the call. The cleanup functions of
+// those values are run once the function returns, their errors are returned
+// along with the error of the function.
+func (container *Container) Invoke(fn interface{}) error {
+	return container.InvokeWith(context.Background(), fn)
+}
+
+// InvokeWith works like Invoke but seeds the resolution with scoped values
+// for the exact type of each value, e.g. the user of the current request.
+// Scoped values only apply to this call and are never cached by the
+// container.
+//
+//   err := container.InvokeWith(ctx, func(handler *Handler) error {…}, currentUser)
+func (container *Container) InvokeWith(ctx context.Context, fn interface{}, scoped ...interface{}) error {
+	_type := reflect.TypeOf(fn)
+
+	if _type == nil || _type.Kind() != reflect.Func || _type.NumOut() > 1 || _type.NumOut() == 1 && _type.Out(0) != errorType {
+		return fmt.Errorf("Function '%s' must be a function returning nothing or an error", _type)
+	}
+
+	return container.invoke(ctx, fn, scoped, false, func(results []reflect.Value) error {
+		if len(results) == 1 && !results[0].IsNil() {
+			return results[0].Interface().(error)
+		}
+
+		return nil
+	})
+}
+
+// InvokeResult works like Invoke but returns the value returned by the
+// function. The function may return an error as second value. Since the
+// value may hold the values constructed for the call, their cleanup functions
+// are kept until the container is disposed instead of being run once the
+// function returns, unless it fails.
+//
+//   client, err := injector.InvokeResult[*Client](container, func(config *Config) (*Client, error) {
+//     return Dial(config.Address)
+//   })
+func InvokeResult[R any](container *Container, fn interface{}) (R, error) {
+	var result R
+
+	resultType := reflect.TypeOf((*R)(nil)).Elem()
+	_type := reflect.TypeOf(fn)
+
+	if _type == nil || _type.Kind() != reflect.Func || _type.NumOut() < 1 || _type.NumOut() > 2 ||
+		!_type.Out(0).AssignableTo(resultType) || _type.NumOut() == 2 && _type.Out(1) != errorType {
+		return result, fmt.Errorf("Function '%s' must be a function returning '%s' optionally followed by an error", _type, resultType)
+	}
+
+	err := container.invoke(context.Background(), fn, nil, true, func(results []reflect.Value) error {
+		if len(results) == 2 && !results[1].IsNil() {
+			return results[1].Interface().(error)
+		}
+
+		reflect.ValueOf(&result).Elem().Set(results[0])
+
+		return nil
+	})
+
+	return result, err
+}
+
+// invoke calls the function with resolved parameters, passes its results to
+// the handler and runs the cleanup functions afterwards. If the results are
+// owned, the cleanup functions of a successful call are kept until the
+// container is disposed instead.
+func (container *Container) invoke(ctx context.Context, fn interface{}, scoped []interface{}, owned bool, handle func([]reflect.Value) error) error {
+	if err := container.checkDisposed(); err != nil {
+		return err
+	}
+
+	_type := reflect.TypeOf(fn)
+
+	resolver := newResolver(ctx, container)
+
+	// Singletons of the container are shared, but the values constructed for
+	// the call are torn down once it returns and are never cached
+	resolver.Singletons = len(scoped) == 0
+
+	for _, value := range scoped {
+		if value == nil {
+			return fmt.Errorf("Scoped value must not be nil")
+		}
+
+		if err := resolver.override(reflect.TypeOf(value), value); err != nil {
+			return err
+		}
+	}
+
+	var arguments []reflect.Value
+
+	for i := 0; i < _type.NumIn(); i++ {
+		argument, err := resolver.resolveDependency(&dependency{Type: _type.In(i)})
+
+		if err != nil {
+			return container.abort(err, resolver)
+		}
+
+		arguments = append(arguments, argument)
+	}
+
+	err := handle(reflect.ValueOf(fn).Call(arguments))
+
+	if owned && err == nil {
+		container.lock.Lock()
+
+		if !container.disposed {
+			container.cleanups = append(container.cleanups, resolver.Cleanups...)
+			container.lock.Unlock()
+
+			return nil
+		}
+
+		container.lock.Unlock()
+
+		err = ErrDisposed
+	}
+
+	if cleanupErr := runCleanups(resolver.Cleanups); cleanupErr != nil {
+		return errors.Join(err, cleanupErr)
+	}
+
+	return err
+}
@@ -0,0 +1,140 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type InvokeUser struct {
+	name string
+}
+
+type InvokeGreeter struct {
+	user *InvokeUser
+}
+
+func NewInvokeGreeter(user *InvokeUser) *InvokeGreeter {
+	return &InvokeGreeter{
+		user: user,
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewInvokeGreeter, func() *InvokeUser {
+		return &InvokeUser{name: "default"}
+	})
+
+	invoked := false
+
+	err := container.Invoke(func(greeter *InvokeGreeter) {
+		invoked = greeter.user.name == "default"
+	})
+
+	if err != nil || !invoked {
+		t.Errorf("Function could not be invoked: %v", err)
+	}
+
+	expected := errors.New("failed")
+
+	if err := container.Invoke(func(*InvokeGreeter) error { return expected }); err != expected {
+		t.Errorf("Error of function was not returned")
+	}
+}
+
+func TestInvokeWithScoped(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewInvokeGreeter)
+
+	var names []string
+
+	greet := func(greeter *InvokeGreeter) {
+		names = append(names, greeter.user.name)
+	}
+
+	for _, name := range []string{"alice", "bob"} {
+		if err := container.InvokeWith(context.Background(), greet, &InvokeUser{name: name}); err != nil {
+			t.Fatalf("Function could not be invoked: %s", err)
+		}
+	}
+
+	if len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+		t.Errorf("Scoped values leaked between invocations: %v", names)
+	}
+
+	if err := container.Invoke(greet); err == nil {
+		t.Errorf("Scoped value leaked into the container")
+	}
+}
+
+func TestInvokeResult(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *InvokeUser {
+		return &InvokeUser{name: "alice"}
+	})
+
+	greeter, err := InvokeResult[*InvokeGreeter](container, NewInvokeGreeter)
+
+	if err != nil || greeter.user.name != "alice" {
+		t.Errorf("Result could not be returned: %v", err)
+	}
+
+	expected := errors.New("failed")
+
+	_, err = InvokeResult[*InvokeGreeter](container, func(user *InvokeUser) (*InvokeGreeter, error) {
+		return nil, expected
+	})
+
+	if err != expected {
+		t.Errorf("Error of function was not returned")
+	}
+
+	if _, err := InvokeResult[string](container, NewInvokeGreeter); err == nil {
+		t.Errorf("Function returning the wrong type was not rejected")
+	}
+}
+
+type InvokeConnection struct {
+	closed bool
+}
+
+type InvokeClient struct {
+	connection *InvokeConnection
+}
+
+func (client *InvokeClient) Send() error {
+	if client.connection.closed {
+		return errors.New("connection closed")
+	}
+
+	return nil
+}
+
+func TestInvokeResultCleanups(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() (*InvokeConnection, func()) {
+		connection := &InvokeConnection{}
+		return connection, func() { connection.closed = true }
+	})
+
+	client, err := InvokeResult[*InvokeClient](container, func(connection *InvokeConnection) *InvokeClient {
+		return &InvokeClient{connection: connection}
+	})
+
+	if err != nil {
+		t.Fatalf("Client could not be returned: %s", err)
+	}
+
+	if err := client.Send(); err != nil {
+		t.Errorf("Returned client was torn down by InvokeResult: %s", err)
+	}
+
+	if err := container.Dispose(); err != nil || client.Send() == nil {
+		t.Errorf("Connection of the returned client was not closed by Dispose: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type keyedConstructor struct {
+	Constructor *constructor
+	KeyType     reflect.Type
+	Instances   map[interface{}]reflect.Value
+}
+
+// RegisterKeyed registers a constructor that produces one singleton per key.
+// The first parameter of the constructor is the key, the remaining parameters
+// are injected like the parameters of any other constructor.
+//
+//   container.RegisterKeyed(func(id ShardID, config *Config) *Shard {…})
+//
+//   shard, err := injector.GetKeyed[*Shard](container, ShardID(3))
+//
+// Keys are compared with ==, so the key type must be comparable. Keyed
+// constructors are only used by GetKeyed and are never injected into other
+// constructors.
+func (container *Container) RegisterKeyed(_constructor interface{}) {
+	if err := container.RegisterKeyedE(_constructor); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterKeyedE works like RegisterKeyed but returns an error instead of
+// panicking if the constructor cannot be registered.
+func (container *Container) RegisterKeyedE(_constructor interface{}) error {
+	details, err := parseConstructor(_constructor)
+
+	if err != nil {
+		return err
+	}
+
+	if len(details.Parameters) == 0 {
+		return fmt.Errorf("Keyed constructor '%s' must take the key as first parameter", details.Function.Type())
+	}
+
+	keyType := details.Parameters[0]
+
+	if !keyType.Comparable() {
+		return fmt.Errorf("Key type '%s' of keyed constructor '%s' is not comparable", keyType, details.Function.Type())
+	}
+
+	if details.Cleanup {
+		return fmt.Errorf("Keyed constructor '%s' must not return a cleanup function", details.Function.Type())
+	}
+
+	if container.keyedResolver == nil {
+		container.keyedResolver = newKeyedResolver(container)
+	}
+
+	container.keyedConstructors = append(container.keyedConstructors, &keyedConstructor{
+		Constructor: details,
+		KeyType:     keyType,
+		Instances:   make(map[interface{}]reflect.Value),
+	})
+
+	return nil
+}
+
+// GetKeyed returns the value of type T for the given key. The first call for a
+// key invokes the keyed constructor, later calls return the same value.
+// Dependencies of keyed constructors are shared by all keys.
+func GetKeyed[T any](container *Container, key interface{}) (T, error) {
+	var result T
+
+	_type := reflect.TypeOf((*T)(nil)).Elem()
+
+	_resolver := container.keyedResolver
+
+	if _resolver == nil {
+		return result, missingDependency(_type, "No keyed constructor defined for type '%s'", _type)
+	}
+
+	_resolver.Lock.Lock()
+	defer _resolver.Lock.Unlock()
+
+	if err := container.checkDisposed(); err != nil {
+		return result, err
+	}
+
+	keyValue := reflect.ValueOf(key)
+
+	if !keyValue.IsValid() {
+		return result, fmt.Errorf("Key for type '%s' must not be nil", _type)
+	}
+
+	keyed, err := container.findKeyedConstructor(_type, keyValue.Type())
+
+	if err != nil {
+		return result, err
+	}
+
+	keyValue = keyValue.Convert(keyed.KeyType)
+
+	value, ok := keyed.Instances[keyValue.Interface()]
+
+	if !ok {
+		arguments := []reflect.Value{keyValue}
+
+		for _, dependency := range keyed.Constructor.Dependencies[1:] {
+			argument, err := _resolver.constructOwned(func() (reflect.Value, error) {
+				return _resolver.resolveDependency(dependency)
+			})
+
+			if err != nil {
+				return result, err
+			}
+
+			arguments = append(arguments, argument)
+		}
+
+		results, err := _resolver.call(keyed.Constructor, arguments)
+
+		if err != nil {
+			return result, err
+		}
+
+		value = results[0]
+		keyed.Instances[keyValue.Interface()] = value
+
+		_resolver.countInstance(keyed.Constructor)
+	}
+
+	reflect.ValueOf(&result).Elem().Set(value)
+
+	return result, nil
+}
+
+// newKeyedResolver creates the resolver of the dependencies of keyed
+// constructors, which are singletons of the container like the values of any
+// other resolution.
+func newKeyedResolver(container *Container) *resolver {
+	_resolver := newResolver(context.Background(), container)
+	_resolver.Singletons = true
+
+	return _resolver
+}
+
+func (container *Container) findKeyedConstructor(_type, keyType reflect.Type) (*keyedConstructor, error) {
+	var found *keyedConstructor
+
+	for _, keyed := range container.keyedConstructors {
+		if !keyed.Constructor.ReturnType.AssignableTo(_type) || !keyType.AssignableTo(keyed.KeyType) {
+			continue
+		}
+
+		if found != nil {
+			return nil, ambiguousDependency(_type, "Ambiguity detected for keyed type '%s' with key type '%s'", _type, keyType)
+		}
+
+		found = keyed
+	}
+
+	if found == nil {
+		return nil, missingDependency(_type, "No keyed constructor defined for type '%s' with key type '%s'", _type, keyType)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,84 @@
+package injector
+
+import (
+	"testing"
+)
+
+type KeyedShardID int
+
+type KeyedConfig struct {
+	prefix string
+}
+
+type KeyedShard struct {
+	id     KeyedShardID
+	config *KeyedConfig
+}
+
+func NewKeyedShard(id KeyedShardID, config *KeyedConfig) *KeyedShard {
+	return &KeyedShard{
+		id:     id,
+		config: config,
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *KeyedConfig {
+		return &KeyedConfig{prefix: "shard"}
+	})
+	container.RegisterKeyed(NewKeyedShard)
+
+	first, err := GetKeyed[*KeyedShard](container, KeyedShardID(1))
+
+	if err != nil {
+		t.Fatalf("Shard could not be resolved: %s", err)
+	}
+
+	second, err := GetKeyed[*KeyedShard](container, KeyedShardID(2))
+
+	if err != nil {
+		t.Fatalf("Shard could not be resolved: %s", err)
+	}
+
+	again, _ := GetKeyed[*KeyedShard](container, KeyedShardID(1))
+
+	if first == second || first.id != 1 || second.id != 2 {
+		t.Errorf("Shards were not created per key")
+	}
+
+	if first != again {
+		t.Errorf("Shard was not cached for its key")
+	}
+
+	if first.config == nil || first.config != second.config {
+		t.Errorf("Config was not shared between keys")
+	}
+}
+
+func TestKeyedMissing(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterKeyed(func(id KeyedShardID) *KeyedShard {
+		return &KeyedShard{id: id}
+	})
+
+	if _, err := GetKeyed[*KeyedShard](container, "1"); err == nil {
+		t.Errorf("Key of wrong type was not rejected")
+	}
+}
+
+func TestKeyedIncomparable(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Incomparable key was not rejected")
+		}
+	}()
+
+	container := NewContainer()
+
+	container.RegisterKeyed(func(ids []KeyedShardID) *KeyedShard {
+		return &KeyedShard{}
+	})
+}
@@ -0,0 +1,51 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// Lazy defers constructing a value of T until Get is called for the first
+// time instead of constructing it when the lazy value is injected.
+//
+//   type App struct {
+//     Reports *injector.Lazy[*ReportGenerator]
+//   }
+//
+//   generator, err := app.Reports.Get()
+//
+// The value is a singleton shared with the resolution that injected the lazy
+// value. Get is safe for concurrent use.
+type Lazy[T any] struct {
+	resolver *resolver
+}
+
+func (lazy *Lazy[T]) synthesize(_resolver *resolver) error {
+	lazy.resolver = _resolver
+	return nil
+}
+
+// Get returns the value of T, constructing it on the first call.
+func (lazy *Lazy[T]) Get() (T, error) {
+	var result T
+
+	_resolver := lazy.resolver
+
+	_resolver.Lock.Lock()
+	defer _resolver.Lock.Unlock()
+
+	if err := _resolver.Container.checkDisposed(); err != nil {
+		return result, err
+	}
+
+	value, err := _resolver.constructOwned(func() (reflect.Value, error) {
+		return _resolver.resolveType(reflect.TypeOf((*T)(nil)).Elem())
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	reflect.ValueOf(&result).Elem().Set(value)
+
+	return result, nil
+}
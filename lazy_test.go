@@ -0,0 +1,163 @@
+package injector
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type LazyApp struct {
+	Reports *Lazy[*LazyReports]
+}
+
+type LazyReports struct {
+	name string
+}
+
+func TestLazy(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(func() *LazyReports {
+		constructed++
+		return &LazyReports{name: "reports"}
+	})
+
+	app := &LazyApp{}
+	container.Resolve(app)
+
+	if constructed != 0 {
+		t.Errorf("Reports were constructed at resolve time")
+	}
+
+	var wait sync.WaitGroup
+
+	results := make([]*LazyReports, 4)
+
+	for i := range results {
+		wait.Add(1)
+
+		go func(i int) {
+			defer wait.Done()
+			results[i], _ = app.Reports.Get()
+		}(i)
+	}
+
+	wait.Wait()
+
+	if constructed != 1 {
+		t.Errorf("Reports were constructed %d times", constructed)
+	}
+
+	for _, reports := range results {
+		if reports == nil || reports != results[0] {
+			t.Errorf("Reports were not a singleton")
+		}
+	}
+}
+
+func TestLazyDispose(t *testing.T) {
+	container := NewContainer()
+
+	closed := false
+
+	container.Register(func() (*LazyReports, func()) {
+		return &LazyReports{name: "reports"}, func() { closed = true }
+	})
+
+	app := &LazyApp{}
+	container.Resolve(app)
+
+	if _, err := app.Reports.Get(); err != nil {
+		t.Fatalf("Reports could not be constructed: %s", err)
+	}
+
+	if err := container.Dispose(); err != nil || !closed {
+		t.Errorf("Cleanup of lazily constructed reports was not run by Dispose: %v", err)
+	}
+
+	if _, err := app.Reports.Get(); err != ErrDisposed {
+		t.Errorf("Expected ErrDisposed after Dispose, got %v", err)
+	}
+}
+
+type LazyExporterApp struct {
+	Exporter *Lazy[*LazyExporter]
+}
+
+type LazyExporter struct {
+	reports *LazyReports
+	format  LazyFormat
+	job     *LazyJob
+}
+
+type LazyFormat interface {
+	Extension() string
+}
+
+type LazyCSV struct{}
+
+func (LazyCSV) Extension() string { return "csv" }
+
+type LazyJob struct{}
+
+func TestLazyNested(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *LazyReports {
+		return &LazyReports{name: "reports"}
+	})
+
+	container.RegisterNamed("csv", func() LazyFormat {
+		return LazyCSV{}
+	})
+
+	container.RegisterTransient(func() *LazyJob {
+		return &LazyJob{}
+	})
+
+	container.Register(func(reports *Lazy[*LazyReports], formats *Registry[LazyFormat], jobs *Factory[*LazyJob]) (*LazyExporter, error) {
+		exporter := &LazyExporter{}
+
+		var err error
+
+		if exporter.reports, err = reports.Get(); err != nil {
+			return nil, err
+		}
+
+		if exporter.format, err = formats.Get("csv"); err != nil {
+			return nil, err
+		}
+
+		if exporter.job, err = jobs.New(); err != nil {
+			return nil, err
+		}
+
+		return exporter, nil
+	})
+
+	app := &LazyExporterApp{}
+	container.Resolve(app)
+
+	done := make(chan error, 1)
+
+	go func() {
+		exporter, err := app.Exporter.Get()
+
+		if err == nil && (exporter.reports == nil || exporter.format == nil || exporter.job == nil) {
+			t.Errorf("Exporter was constructed without its nested dependencies")
+		}
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Exporter could not be constructed: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Nested lazy, registry and factory access deadlocked")
+	}
+}
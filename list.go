@@ -0,0 +1,43 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// List holds all values of T like a slice of T does, as a typed alternative
+// for consumers preferring a collection type.
+//
+//   func NewRouter(handlers *injector.List[Handler]) *Router {…}
+//
+// List is the only generic collection the container fills. A list is filled
+// like []T, so deduplication, filters and comparators of T apply.
+type List[T any] struct {
+	values []T
+}
+
+func (list *List[T]) synthesize(_resolver *resolver) error {
+	value, err := _resolver.resolveType(reflect.TypeOf(list.values))
+
+	if err != nil {
+		return err
+	}
+
+	list.values = value.Interface().([]T)
+
+	return nil
+}
+
+// Len returns the number of values.
+func (list *List[T]) Len() int {
+	return len(list.values)
+}
+
+// At returns the value at the index.
+func (list *List[T]) At(index int) T {
+	return list.values[index]
+}
+
+// Values returns a copy of the values.
+func (list *List[T]) Values() []T {
+	return append([]T(nil), list.values...)
+}
@@ -0,0 +1,54 @@
+package injector
+
+import (
+	"testing"
+)
+
+type ListApp struct {
+	Router *ListRouter
+}
+
+type ListRouter struct {
+	handlers *List[ListHandler]
+}
+
+type ListHandler interface {
+	Path() string
+}
+
+type ListPathHandler struct {
+	path string
+}
+
+func (handler *ListPathHandler) Path() string {
+	return handler.path
+}
+
+func NewListRouter(handlers *List[ListHandler]) *ListRouter {
+	return &ListRouter{
+		handlers: handlers,
+	}
+}
+
+func TestList(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		NewListRouter,
+		func() *ListPathHandler { return &ListPathHandler{path: "/users"} },
+		func() ListHandler { return &ListPathHandler{path: "/status"} },
+	)
+
+	app := &ListApp{}
+	container.Resolve(app)
+
+	handlers := app.Router.handlers
+
+	if handlers.Len() != 2 || handlers.At(0).Path() != "/users" || handlers.At(1).Path() != "/status" {
+		t.Errorf("List could not be resolved")
+	}
+
+	if values := handlers.Values(); len(values) != 2 || values[0] != handlers.At(0) {
+		t.Errorf("Values of list could not be copied")
+	}
+}
@@ -0,0 +1,59 @@
+package injector
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// reentrantLock is a mutex the goroutine holding it can lock again. A
+// resolver stays locked while it constructs values after the initial
+// resolution, and the constructors may use lazy values, registries,
+// factories and scopes of the same resolver.
+type reentrantLock struct {
+	mutex sync.Mutex
+	owner atomic.Int64
+	depth int
+}
+
+func (lock *reentrantLock) Lock() {
+	id := goroutineID()
+
+	if lock.owner.Load() == id {
+		lock.depth++
+		return
+	}
+
+	lock.mutex.Lock()
+	lock.owner.Store(id)
+	lock.depth = 1
+}
+
+func (lock *reentrantLock) Unlock() {
+	lock.depth--
+
+	if lock.depth > 0 {
+		return
+	}
+
+	lock.owner.Store(0)
+	lock.mutex.Unlock()
+}
+
+// goroutineID returns the ID of the calling goroutine, taken from the
+// header "goroutine 42 [running]:" of its stack trace.
+func goroutineID() int64 {
+	var buffer [64]byte
+
+	stack := bytes.TrimPrefix(buffer[:runtime.Stack(buffer[:], false)], []byte("goroutine "))
+
+	if end := bytes.IndexByte(stack, ' '); end >= 0 {
+		stack = stack[:end]
+	}
+
+	id, _ := strconv.ParseInt(string(stack), 10, 64)
+
+	return id
+}
@@ -0,0 +1,55 @@
+package injector
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+var slogLoggerType = reflect.TypeOf((*slog.Logger)(nil))
+
+// Logger is a structured logger tagged with the type of the constructor it is
+// injected into, so log entries tell which component emitted them.
+//
+//   func NewUserService(logger *injector.Logger) *UserService {
+//     logger.Info("starting") // component=*app.UserService msg=starting
+//     …
+//   }
+//
+// Entries are written to the registered *slog.Logger or to slog.Default if
+// none is registered. A logger injected into a root is tagged with the type
+// of the root, e.g. component=*app.App, one injected into a function called
+// by Invoke with component=root.
+type Logger struct {
+	*slog.Logger
+}
+
+func (logger *Logger) synthesize(_resolver *resolver) error {
+	base := slog.Default()
+
+	if len(_resolver.Container.findConstructors(slogLoggerType)) > 0 {
+		value, err := _resolver.resolveType(slogLoggerType)
+
+		if err != nil {
+			return err
+		}
+
+		// Loggers are not constructed during a dry run
+		if !value.IsNil() {
+			base = value.Interface().(*slog.Logger)
+		}
+	}
+
+	component := "root"
+
+	if _resolver.RootType != nil {
+		component = _resolver.RootType.String()
+	}
+
+	if pending := _resolver.PendingConstructors; len(pending) > 0 {
+		component = pending[len(pending)-1].ReturnType.String()
+	}
+
+	logger.Logger = base.With("component", component)
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package injector
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type LoggerApp struct {
+	Users  *LoggerUsers
+	Orders *LoggerOrders
+}
+
+type LoggerUsers struct {
+}
+
+type LoggerOrders struct {
+}
+
+func NewLoggerUsers(logger *Logger) *LoggerUsers {
+	logger.Info("users started")
+	return &LoggerUsers{}
+}
+
+func NewLoggerOrders(logger *Logger, users *LoggerUsers) *LoggerOrders {
+	logger.Info("orders started")
+	return &LoggerOrders{}
+}
+
+func TestLogger(t *testing.T) {
+	var output bytes.Buffer
+
+	container := NewContainer()
+
+	container.RegisterInstance(slog.New(slog.NewTextHandler(&output, nil)))
+	container.Register(NewLoggerUsers, NewLoggerOrders)
+
+	container.Resolve(&LoggerApp{})
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected log output:\n%s", output.String())
+	}
+
+	if !strings.Contains(lines[0], "users started") || !strings.Contains(lines[0], "component=*injector.LoggerUsers") {
+		t.Errorf("Logger of users was not tagged: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], "orders started") || !strings.Contains(lines[1], "component=*injector.LoggerOrders") {
+		t.Errorf("Logger of orders was not tagged: %s", lines[1])
+	}
+}
+
+type LoggerRootApp struct {
+	Logger *Logger
+}
+
+func TestLoggerRoot(t *testing.T) {
+	var output bytes.Buffer
+
+	container := NewContainer()
+
+	container.RegisterInstance(slog.New(slog.NewTextHandler(&output, nil)))
+
+	app := &LoggerRootApp{}
+	container.Resolve(app)
+
+	app.Logger.Info("app started")
+
+	container.Invoke(func(logger *Logger) {
+		logger.Info("invoked")
+	})
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected log output:\n%s", output.String())
+	}
+
+	if !strings.Contains(lines[0], "component=*injector.LoggerRootApp") {
+		t.Errorf("Logger of the root was not tagged with its type: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[1], "component=root") {
+		t.Errorf("Logger of the invoked function was not tagged as root: %s", lines[1])
+	}
+}
@@ -0,0 +1,116 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge creates a container combining the registrations of the container and
+// the other containers, e.g. to assemble an app from subsystems each owning a
+// container.
+//
+//   app := core.Merge(storage, http)
+//
+// The merged container uses the options of the container Merge is called on.
+// Only registrations are merged, values already constructed by one of the
+// containers are constructed again by the merged container.
+//
+// A plain constructor, one that is neither named, a group member nor a
+// decorator, whose return type is also returned by a plain constructor of
+// another container is a conflict and causes Merge to panic. Constructors of
+// an ancestor shared by several of the containers are merged once.
+func (container *Container) Merge(others ...*Container) *Container {
+	merged, err := container.MergeE(others...)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return merged
+}
+
+// MergeE works like Merge but returns an error instead of panicking if the
+// containers conflict.
+func (container *Container) MergeE(others ...*Container) (*Container, error) {
+	merged := &Container{
+		diagnosticHandler: container.diagnosticHandler,
+		keyByType:         container.keyByType,
+		filterByType:      container.filterByType,
+		depthOrdered:      container.depthOrdered,
+
+		explicitInterfaces: container.explicitInterfaces,
+		clock:              container.clock,
+		strictPointers:     container.strictPointers,
+		verboseErrors:      container.verboseErrors,
+		cycleHandler:       container.cycleHandler,
+		mode:               container.mode,
+		zeroAllocation:     container.zeroAllocation,
+		strictExports:      container.strictExports,
+	}
+
+	owners := make(map[reflect.Type]*Container)
+	copiedConstructors := make(map[*constructor]bool)
+
+	for _, source := range append([]*Container{container}, others...) {
+		for _, constructor := range source.allConstructors() {
+			// Children of the same parent share the constructors of the parent
+			if copiedConstructors[constructor] {
+				continue
+			}
+
+			copiedConstructors[constructor] = true
+
+			if constructor.plain() {
+				if owner, ok := owners[constructor.ReturnType]; ok && owner != source {
+					return nil, fmt.Errorf("Type '%s' of constructor '%s' is registered with more than one of the merged containers", constructor.ReturnType, constructor.Function.Type())
+				}
+
+				owners[constructor.ReturnType] = source
+			}
+
+			copied := *constructor
+			merged.constructors = append(merged.constructors, &copied)
+		}
+
+		for _, keyed := range source.keyedConstructors {
+			copied := *keyed.Constructor
+
+			merged.keyedConstructors = append(merged.keyedConstructors, &keyedConstructor{
+				Constructor: &copied,
+				KeyType:     keyed.KeyType,
+				Instances:   make(map[interface{}]reflect.Value),
+			})
+		}
+
+		source.mergeGroupConfigs(merged)
+	}
+
+	if len(merged.keyedConstructors) > 0 {
+		merged.keyedResolver = newKeyedResolver(merged)
+	}
+
+	return merged, nil
+}
+
+// plain reports whether the constructor is selected by its type alone.
+func (constructor *constructor) plain() bool {
+	return constructor.Name == "" && len(constructor.Groups) == 0 && !constructor.Decorator
+}
+
+// mergeGroupConfigs copies the group configurations of the container and its
+// ancestors to the merged container, later configurations take precedence.
+func (container *Container) mergeGroupConfigs(merged *Container) {
+	if container.parent != nil {
+		container.parent.mergeGroupConfigs(merged)
+	}
+
+	for group, config := range container.groupConfigs {
+		copied := *config
+
+		if merged.groupConfigs == nil {
+			merged.groupConfigs = make(map[string]*groupConfig)
+		}
+
+		merged.groupConfigs[group] = &copied
+	}
+}
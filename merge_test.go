@@ -0,0 +1,79 @@
+package injector
+
+import (
+	"testing"
+)
+
+type MergeApp struct {
+	Server *MergeServer
+}
+
+type MergeServer struct {
+	store *MergeStore
+}
+
+type MergeStore struct {
+	name string
+}
+
+func NewMergeServer(store *MergeStore) *MergeServer {
+	return &MergeServer{
+		store: store,
+	}
+}
+
+func NewMergeStore() *MergeStore {
+	return &MergeStore{name: "store"}
+}
+
+func TestMerge(t *testing.T) {
+	http := NewContainer()
+	http.Register(NewMergeServer)
+
+	storage := NewContainer()
+	storage.Register(NewMergeStore)
+
+	container := http.Merge(storage)
+
+	app := &MergeApp{}
+	container.Resolve(app)
+
+	if app.Server == nil || app.Server.store == nil || app.Server.store.name != "store" {
+		t.Errorf("Server could not be resolved from the merged containers")
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	first := NewContainer()
+	first.Register(NewMergeStore)
+
+	second := NewContainer()
+	second.Register(NewMergeStore)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Conflicting constructors were not detected")
+		}
+	}()
+
+	first.Merge(second)
+}
+
+func TestMergeSiblings(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(NewMergeStore)
+
+	http := parent.NewChild()
+	http.Register(NewMergeServer)
+
+	worker := parent.NewChild()
+
+	container := http.Merge(worker)
+
+	app := &MergeApp{}
+	container.Resolve(app)
+
+	if app.Server == nil || app.Server.store == nil {
+		t.Errorf("Server could not be resolved from the merged siblings")
+	}
+}
@@ -0,0 +1,71 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// RegisterNamed registers a constructor like Register under the given name.
+// Fields tagged with the name receive the value of that constructor, even if
+// other constructors return the same type. The constructor is also made a
+// member of the given groups.
+//
+//   container.RegisterNamed("sessions", NewRedisCache)
+//   container.RegisterNamed("deploy", NewDeployCommand, "commands")
+func (container *Container) RegisterNamed(name string, _constructor interface{}, groups ...string) {
+	if err := container.RegisterNamedE(name, _constructor, groups...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterNamedE works like RegisterNamed but returns an error instead of
+// panicking if the constructor cannot be registered.
+func (container *Container) RegisterNamedE(name string, _constructor interface{}, groups ...string) error {
+	details, err := parseConstructor(_constructor)
+
+	if err != nil {
+		return err
+	}
+
+	details.Name = name
+	details.Groups = groups
+
+	container.addConstructors(details)
+
+	return nil
+}
+
+func (container *Container) findNamedConstructor(name string, _type reflect.Type) (*constructor, error) {
+	var found *constructor
+
+	for _, constructor := range container.allConstructors() {
+		if constructor.Name != name || !constructor.ReturnType.AssignableTo(_type) {
+			continue
+		}
+
+		if found != nil {
+			return nil, ambiguousDependency(_type, "Ambiguity detected for name '%s' of type '%s'", name, _type)
+		}
+
+		found = constructor
+	}
+
+	if found == nil {
+		return nil, missingDependency(_type, "No constructor named '%s' defined for type '%s'", name, _type)
+	}
+
+	return found, nil
+}
+
+// names returns the names of all named constructors of the given type in
+// registration order.
+func (container *Container) names(_type reflect.Type) []string {
+	var names []string
+
+	for _, constructor := range container.allConstructors() {
+		if constructor.Name != "" && constructor.ReturnType.AssignableTo(_type) {
+			names = append(names, constructor.Name)
+		}
+	}
+
+	return names
+}
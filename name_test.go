@@ -0,0 +1,23 @@
+package injector
+
+import (
+	"testing"
+)
+
+type NamedApp struct {
+	Handler RegistryHandler `inject:"name=stop"`
+}
+
+func TestNamed(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterNamed("start", func() *RegistryStartHandler { return &RegistryStartHandler{} })
+	container.RegisterNamed("stop", func() *RegistryStopHandler { return &RegistryStopHandler{} })
+
+	app := &NamedApp{}
+	container.Resolve(app)
+
+	if app.Handler.Handle() != "stop" {
+		t.Errorf("Handler could not be resolved")
+	}
+}
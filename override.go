@@ -0,0 +1,83 @@
+package injector
+
+import (
+	"fmt"
+)
+
+// Override replaces the registered constructor returning the same type as
+// the given constructor, e.g. to swap a dependency for a fake in tests. The
+// replacement takes the place of the original registration, so it keeps the
+// name, groups, tags, priority and variant of the original, whether it is
+// transient or deferred, and its position in slices.
+//
+//   container.RegisterGroup("middleware", NewAuth, NewRateLimit, NewLogging)
+//   container.Override(func() *RateLimit { return &RateLimit{Disabled: true} })
+//
+// Only constructors registered with the container itself can be overridden.
+// Override panics if there is no such constructor or more than one. The
+// singletons of earlier resolutions constructed by or depending on the
+// replaced constructors are dropped, so the next resolution constructs them
+// again.
+func (container *Container) Override(constructors ...interface{}) {
+	if err := container.OverrideE(constructors...); err != nil {
+		panic(err)
+	}
+}
+
+// OverrideE works like Override but returns an error instead of panicking if
+// a constructor cannot replace a registered one. No constructor is replaced
+// in that case.
+func (container *Container) OverrideE(constructors ...interface{}) error {
+	var changed []*constructor
+
+	replaced := append([]*constructor(nil), container.constructors...)
+
+	for _, _constructor := range constructors {
+		details, err := parseConstructor(_constructor)
+
+		if err != nil {
+			return err
+		}
+
+		index := -1
+
+		for i, original := range replaced {
+			if original.Decorator || original.ReturnType != details.ReturnType {
+				continue
+			}
+
+			if index >= 0 {
+				return fmt.Errorf("Ambiguity detected for overridden type '%s'", details.ReturnType)
+			}
+
+			index = i
+		}
+
+		if index < 0 {
+			return fmt.Errorf("No constructor defined for overridden type '%s'", details.ReturnType)
+		}
+
+		original := replaced[index]
+
+		details.Name = original.Name
+		details.Groups = original.Groups
+		details.Tags = original.Tags
+		details.Priority = original.Priority
+		details.Variant = original.Variant
+		details.Transient = original.Transient
+		details.Deferred = original.Deferred
+
+		if details.Transient && details.Cleanup {
+			return fmt.Errorf("Transient constructor '%s' must not return a cleanup function", details.Function.Type())
+		}
+
+		replaced[index] = details
+
+		changed = append(changed, original, details)
+	}
+
+	container.constructors = replaced
+	container.dropSingletons(changed...)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package injector
+
+import (
+	"testing"
+)
+
+type OverridePositionApp struct {
+	Middleware []OverridePositionMiddleware `inject:"group:middleware"`
+}
+
+type OverridePositionMiddleware interface {
+	Name() string
+}
+
+type OverridePositionAuth struct {
+}
+
+func (middleware *OverridePositionAuth) Name() string {
+	return "auth"
+}
+
+type OverridePositionRateLimit struct {
+	name string
+}
+
+func (middleware *OverridePositionRateLimit) Name() string {
+	return middleware.name
+}
+
+type OverridePositionLogging struct {
+}
+
+func (middleware *OverridePositionLogging) Name() string {
+	return "logging"
+}
+
+func TestOverridePosition(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterGroup(
+		"middleware",
+		func() *OverridePositionAuth { return &OverridePositionAuth{} },
+		func() *OverridePositionRateLimit { return &OverridePositionRateLimit{name: "rate_limit"} },
+		func() *OverridePositionLogging { return &OverridePositionLogging{} },
+	)
+
+	container.Override(func() *OverridePositionRateLimit { return &OverridePositionRateLimit{name: "fake_rate_limit"} })
+
+	app := &OverridePositionApp{}
+	container.Resolve(app)
+
+	var names []string
+
+	for _, middleware := range app.Middleware {
+		names = append(names, middleware.Name())
+	}
+
+	if len(names) != 3 || names[0] != "auth" || names[1] != "fake_rate_limit" || names[2] != "logging" {
+		t.Errorf("Overridden middleware did not keep its position: %v", names)
+	}
+}
+
+func TestOverridePositionMissing(t *testing.T) {
+	container := NewContainer()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Override without original was not rejected")
+		}
+	}()
+
+	container.Override(func() *OverridePositionAuth { return &OverridePositionAuth{} })
+}
+
+type OverrideFlagsApp struct {
+	First  *OverridePositionRateLimit
+	Second *OverridePositionRateLimit
+	Auth   *OverridePositionAuth
+}
+
+func TestOverrideFlags(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterTransient(func() *OverridePositionRateLimit { return &OverridePositionRateLimit{name: "rate_limit"} })
+	container.RegisterDeferred(func() *OverridePositionAuth { return &OverridePositionAuth{} })
+
+	container.Override(
+		func() *OverridePositionRateLimit { return &OverridePositionRateLimit{name: "fake_rate_limit"} },
+		func() *OverridePositionAuth { return &OverridePositionAuth{} },
+	)
+
+	app := &OverrideFlagsApp{}
+	container.Resolve(app)
+
+	if app.First == app.Second || app.First.name != "fake_rate_limit" {
+		t.Errorf("Overridden transient constructor was not transient")
+	}
+
+	if app.Auth != nil {
+		t.Errorf("Overridden deferred constructor was constructed by Resolve")
+	}
+
+	if _, err := container.Activate((*OverridePositionAuth)(nil)); err != nil {
+		t.Errorf("Overridden deferred constructor could not be activated: %s", err)
+	}
+
+	err := container.OverrideE(func() (*OverridePositionRateLimit, func()) {
+		return &OverridePositionRateLimit{}, func() {}
+	})
+
+	if err == nil {
+		t.Errorf("Transient constructor returning a cleanup function replaced the original")
+	}
+}
@@ -0,0 +1,227 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithoutPointerConversion disables injecting a struct for a pointer to it
+// and vice versa. A type is then only injected from constructors whose value
+// is assignable to it.
+func WithoutPointerConversion() Option {
+	return func(container *Container) {
+		container.strictPointers = true
+	}
+}
+
+// convertPointer injects a struct type without a constructor from the
+// constructor of its pointer type and vice versa. A struct receives a copy of
+// the value the pointer points to. A pointer points to a single copy of the
+// struct that is shared by all consumers of the resolution and, like the
+// singletons, by later resolutions of the container. It reports false if no
+// conversion applies.
+func (_resolver *resolver) convertPointer(_type reflect.Type) (reflect.Value, bool, error) {
+	container := _resolver.Container
+
+	if container.strictPointers || len(container.findConstructors(_type)) > 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	switch {
+	case _type.Kind() == reflect.Ptr && _type.Elem().Kind() == reflect.Struct:
+		if len(container.findConstructors(_type.Elem())) == 0 {
+			return reflect.Value{}, false, nil
+		}
+
+		if pointer, ok := _resolver.addressedValue(_type); ok && !_resolver.DryRun {
+			return pointer, true, nil
+		}
+
+		value, err := _resolver.resolveType(_type.Elem())
+
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		pointer := reflect.New(_type.Elem())
+		pointer.Elem().Set(value)
+
+		_resolver.AddressedValues[_type] = pointer
+
+		return pointer, true, nil
+	case _type.Kind() == reflect.Struct:
+		pointerType := reflect.PtrTo(_type)
+
+		if len(container.findConstructors(pointerType)) == 0 {
+			return reflect.Value{}, false, nil
+		}
+
+		pointer, err := _resolver.resolveType(pointerType)
+
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		if _resolver.DryRun {
+			return _resolver.placeholder(_type), true, nil
+		}
+
+		if pointer.IsNil() {
+			return reflect.Value{}, true, fmt.Errorf("Type '%s' cannot be injected from nil pointer of type '%s'", _type, pointerType)
+		}
+
+		copied := reflect.New(_type).Elem()
+		copied.Set(pointer.Elem())
+
+		return copied, true, nil
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+// addressForInterface injects an interface without a constructor from the
+// single constructor returning a struct whose pointer implements the
+// interface. The interface receives the shared pointer to a copy of the
+// struct. It reports false if no such constructor exists.
+func (_resolver *resolver) addressForInterface(_type reflect.Type) (reflect.Value, bool, error) {
+	container := _resolver.Container
+
+	if container.strictPointers || container.explicitInterfaces || _type.Kind() != reflect.Interface || len(container.findConstructors(_type)) > 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	candidates := container.pointerReceiverConstructors(_type)
+
+	if len(candidates) == 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	if len(candidates) > 1 {
+		return reflect.Value{}, true, ambiguousDependency(_type, "Ambiguity detected for type '%s'", _type)
+	}
+
+	value, err := _resolver.resolveType(reflect.PtrTo(candidates[0].ReturnType))
+
+	return value, true, err
+}
+
+// pointerReceiverConstructors returns the constructors returning a struct
+// that only implements the interface with pointer receivers.
+func (container *Container) pointerReceiverConstructors(_type reflect.Type) []*constructor {
+	var constructors []*constructor
+
+	for _, constructor := range container.allConstructors() {
+		returnType := constructor.ReturnType
+
+		if constructor.Decorator || returnType.Kind() != reflect.Struct || returnType.Implements(_type) {
+			continue
+		}
+
+		if reflect.PtrTo(returnType).Implements(_type) {
+			constructors = append(constructors, constructor)
+		}
+	}
+
+	return constructors
+}
+
+// assertInterface injects a concrete type without a constructor from the
+// constructors returning an interface it implements, if exactly one of their
+// values has the concrete type as dynamic type. It reports false if no such
+// constructor exists.
+func (_resolver *resolver) assertInterface(_type reflect.Type) (reflect.Value, bool, error) {
+	container := _resolver.Container
+
+	if _type.Kind() == reflect.Interface || _type.Kind() == reflect.Slice || len(container.findConstructors(_type)) > 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	var candidates []*constructor
+
+	for _, constructor := range container.allConstructors() {
+		if !constructor.Decorator && constructor.ReturnType.Kind() == reflect.Interface && _type.Implements(constructor.ReturnType) && container.activeVariant(constructor) {
+			candidates = append(candidates, constructor)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return reflect.Value{}, false, nil
+	}
+
+	var found []reflect.Value
+
+	for _, constructor := range candidates {
+		value, err := _resolver.provide(constructor, constructor.ReturnType)
+
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		if _resolver.Placeholder[constructor] || !value.IsNil() && value.Elem().Type() == _type {
+			found = append(found, value)
+		}
+	}
+
+	if len(found) == 0 {
+		return reflect.Value{}, true, missingDependency(
+			_type, "No constructor defined for type '%s', constructor '%s' returns an interface whose value is not of that type",
+			_type, candidates[0].Function.Type(),
+		)
+	}
+
+	if len(found) > 1 {
+		return reflect.Value{}, true, ambiguousDependency(_type, "Ambiguity detected for type '%s'", _type)
+	}
+
+	if _resolver.DryRun {
+		return _resolver.placeholder(_type), true, nil
+	}
+
+	return found[0].Elem(), true, nil
+}
+
+// WithZeroAllocation injects a pointer to a zero struct for pointers to plain
+// structs without a constructor instead of failing, e.g. for holder structs
+// filled later. A struct is plain if all of its fields are exported, structs
+// with unexported fields are assumed to need a constructor.
+//
+//   injector.NewContainer(injector.WithZeroAllocation())
+//
+//   func NewServer(options *ServerOptions) *Server {…} // Receives &ServerOptions{}
+//
+// The allocated struct is shared by all consumers of the resolution and by
+// later resolutions of the container like a singleton.
+func WithZeroAllocation() Option {
+	return func(container *Container) {
+		container.zeroAllocation = true
+	}
+}
+
+// allocateZero injects a pointer to a zero plain struct without a
+// constructor. It reports false if no allocation applies.
+func (_resolver *resolver) allocateZero(_type reflect.Type) (reflect.Value, bool) {
+	container := _resolver.Container
+
+	if !container.zeroAllocation || _type.Kind() != reflect.Ptr || _type.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	if len(container.findConstructors(_type)) > 0 || len(container.findConstructors(_type.Elem())) > 0 {
+		return reflect.Value{}, false
+	}
+
+	for i := 0; i < _type.Elem().NumField(); i++ {
+		if _type.Elem().Field(i).PkgPath != "" {
+			return reflect.Value{}, false
+		}
+	}
+
+	if pointer, ok := _resolver.addressedValue(_type); ok {
+		return pointer, true
+	}
+
+	pointer := reflect.New(_type.Elem())
+	_resolver.AddressedValues[_type] = pointer
+
+	return pointer, true
+}
@@ -0,0 +1,215 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type PointerConversionConfig struct {
+	port int
+}
+
+type PointerConversionValueApp struct {
+	Config PointerConversionConfig
+}
+
+type PointerConversionPointerApp struct {
+	First  *PointerConversionConfig
+	Second *PointerConversionConfig
+}
+
+func TestPointerDereference(t *testing.T) {
+	container := NewContainer()
+
+	config := &PointerConversionConfig{port: 8080}
+
+	container.Register(func() *PointerConversionConfig {
+		return config
+	})
+
+	app := &PointerConversionValueApp{}
+	container.Resolve(app)
+
+	config.port = 9090
+
+	if app.Config.port != 8080 {
+		t.Errorf("Config was not copied from pointer")
+	}
+}
+
+func TestPointerAddress(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() PointerConversionConfig {
+		return PointerConversionConfig{port: 8080}
+	})
+
+	app := &PointerConversionPointerApp{}
+	container.Resolve(app)
+
+	if app.First == nil || app.First.port != 8080 {
+		t.Errorf("Config could not be addressed")
+	}
+
+	if app.First != app.Second {
+		t.Errorf("Addressed config was not shared")
+	}
+}
+
+func TestWithoutPointerConversion(t *testing.T) {
+	container := NewContainer(WithoutPointerConversion())
+
+	container.Register(func() *PointerConversionConfig {
+		return &PointerConversionConfig{}
+	})
+
+	if err := container.ResolveContext(context.Background(), &PointerConversionValueApp{}); err == nil {
+		t.Errorf("Pointer was converted in strict mode")
+	}
+}
+
+type PointerReceiverApp struct {
+	Handler PointerReceiverHandler
+	Thing   *PointerReceiverThing
+}
+
+type PointerReceiverHandler interface {
+	Handle() string
+}
+
+type PointerReceiverThing struct {
+	name string
+}
+
+func (thing *PointerReceiverThing) Handle() string {
+	return thing.name
+}
+
+func NewPointerReceiverThing() PointerReceiverThing {
+	return PointerReceiverThing{name: "thing"}
+}
+
+func TestPointerReceiver(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewPointerReceiverThing)
+
+	app := &PointerReceiverApp{}
+	container.Resolve(app)
+
+	if app.Handler == nil || app.Handler.Handle() != "thing" {
+		t.Errorf("Handler could not be resolved from value")
+	}
+
+	if app.Handler != app.Thing {
+		t.Errorf("Addressed value was not shared")
+	}
+}
+
+func TestPointerReceiverStrict(t *testing.T) {
+	container := NewContainer(WithoutPointerConversion())
+
+	container.Register(NewPointerReceiverThing)
+
+	err := container.ResolveContext(context.Background(), &PointerReceiverApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "pointer receivers") {
+		t.Errorf("Pointer receiver mismatch was not explained: %v", err)
+	}
+}
+
+type ZeroAllocationServer struct {
+	options *ZeroAllocationOptions
+}
+
+type ZeroAllocationOptions struct {
+	Port int
+}
+
+type ZeroAllocationState struct {
+	port int
+}
+
+func NewZeroAllocationServer(options *ZeroAllocationOptions) *ZeroAllocationServer {
+	return &ZeroAllocationServer{
+		options: options,
+	}
+}
+
+func TestZeroAllocation(t *testing.T) {
+	container := NewContainer(WithZeroAllocation())
+
+	container.Register(NewZeroAllocationServer)
+
+	app := &struct {
+		Server  *ZeroAllocationServer
+		Options *ZeroAllocationOptions
+	}{}
+
+	container.Resolve(app)
+
+	if app.Server.options == nil || app.Server.options != app.Options {
+		t.Errorf("Options could not be allocated")
+	}
+
+	err := container.ResolveContext(context.Background(), &struct{ State *ZeroAllocationState }{})
+
+	if err == nil {
+		t.Errorf("Struct with unexported fields was allocated")
+	}
+
+	err = NewContainer().ResolveContext(context.Background(), &struct{ Options *ZeroAllocationOptions }{})
+
+	if err == nil {
+		t.Errorf("Options were allocated without the option")
+	}
+}
+
+type PointerConversionSingleApp struct {
+	Config *PointerConversionConfig
+}
+
+func TestPointerAcrossResolutions(t *testing.T) {
+	container := NewContainer(WithZeroAllocation())
+
+	container.Register(func() PointerConversionConfig {
+		return PointerConversionConfig{port: 8080}
+	}, NewZeroAllocationServer)
+
+	var first, second PointerConversionSingleApp
+	container.Resolve(&first)
+	container.Resolve(&second)
+
+	if first.Config == nil || first.Config != second.Config {
+		t.Errorf("Addressed config was not shared across resolutions")
+	}
+
+	server := &struct{ Server *ZeroAllocationServer }{}
+	container.Resolve(server)
+
+	options := &struct{ Options *ZeroAllocationOptions }{}
+	container.Resolve(options)
+
+	if server.Server.options == nil || server.Server.options != options.Options {
+		t.Errorf("Allocated options were not shared across resolutions")
+	}
+
+	container.Override(func() PointerConversionConfig {
+		return PointerConversionConfig{port: 9090}
+	})
+
+	var replaced PointerConversionSingleApp
+	container.Resolve(&replaced)
+
+	if replaced.Config == first.Config || replaced.Config.port != 9090 {
+		t.Errorf("Addressed config was reused after an override")
+	}
+
+	var again struct{ Options *ZeroAllocationOptions }
+	container.Resolve(&again)
+
+	if again.Options != options.Options {
+		t.Errorf("Allocated options were dropped by an unrelated override")
+	}
+}
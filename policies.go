@@ -0,0 +1,56 @@
+package injector
+
+// Policies is a read-only view of the policies of the container that resolves
+// it. Inject it into components that adapt their behavior to the container,
+// e.g. tooling built on top of it.
+//
+//   func NewWiringReport(policies *injector.Policies) *WiringReport {…}
+type Policies struct {
+	explicitInterfaces bool
+	pointerConversion  bool
+	verboseErrors      bool
+	scoped             bool
+	dryRun             bool
+}
+
+func (policies *Policies) synthesize(_resolver *resolver) error {
+	container := _resolver.Container
+
+	policies.explicitInterfaces = container.explicitInterfaces
+	policies.pointerConversion = !container.strictPointers
+	policies.verboseErrors = container.verboseErrors
+	policies.scoped = container.parent != nil
+	policies.dryRun = _resolver.DryRun
+
+	return nil
+}
+
+// ExplicitInterfaces reports whether interfaces are only resolved from
+// constructors bound to them, see WithExplicitInterfaces. Otherwise every
+// implementation is a candidate and several of them are ambiguous.
+func (policies *Policies) ExplicitInterfaces() bool {
+	return policies.explicitInterfaces
+}
+
+// PointerConversion reports whether structs and pointers to structs are
+// converted into each other, see WithoutPointerConversion.
+func (policies *Policies) PointerConversion() bool {
+	return policies.pointerConversion
+}
+
+// VerboseErrors reports whether errors explain what was constructed before
+// the failure, see WithVerboseErrors.
+func (policies *Policies) VerboseErrors() bool {
+	return policies.verboseErrors
+}
+
+// Scoped reports whether the container is a child container whose
+// constructors shadow those of its parent.
+func (policies *Policies) Scoped() bool {
+	return policies.scoped
+}
+
+// DryRun reports whether the resolution only validates the graph.
+func (policies *Policies) DryRun() bool {
+	return policies.dryRun
+}
@@ -0,0 +1,41 @@
+package injector
+
+import (
+	"testing"
+)
+
+type PoliciesApp struct {
+	Report *PoliciesReport
+}
+
+type PoliciesReport struct {
+	explicit bool
+	scoped   bool
+}
+
+func NewPoliciesReport(policies *Policies) *PoliciesReport {
+	return &PoliciesReport{
+		explicit: policies.ExplicitInterfaces(),
+		scoped:   policies.Scoped(),
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	container := NewContainer(WithExplicitInterfaces())
+
+	container.Register(NewPoliciesReport)
+
+	app := &PoliciesApp{}
+	container.Resolve(app)
+
+	if !app.Report.explicit || app.Report.scoped {
+		t.Errorf("Policies could not be resolved")
+	}
+
+	app = &PoliciesApp{}
+	container.NewChild().Resolve(app)
+
+	if !app.Report.explicit || !app.Report.scoped {
+		t.Errorf("Policies of child could not be resolved")
+	}
+}
@@ -0,0 +1,44 @@
+package injector
+
+import (
+	"sort"
+)
+
+// RegisterWithPriority registers constructors like Register with a priority.
+// Constructors registered with Register have a priority of zero.
+//
+//   container.RegisterWithPriority(10, NewRedisCache)
+//   container.RegisterWithPriority(0, NewMemoryCache)
+//
+// A single value of an implemented interface is taken from the constructor
+// with the highest priority, which must be unique. Priorities are only
+// compared between the constructors of the nearest container defining the
+// type, so a child shadows its ancestors regardless of their priorities.
+// Slices receive the values of all constructors ordered by descending
+// priority, constructors of equal priority keep their registration order.
+func (container *Container) RegisterWithPriority(priority int, constructors ...interface{}) {
+	for _, _constructor := range constructors {
+		details := newConstructor(_constructor)
+		details.Priority = priority
+
+		container.addConstructors(details)
+	}
+}
+
+// highestPriority returns the constructor with the highest priority if there
+// are several constructors and that priority is unique.
+func highestPriority(constructors []*constructor) *constructor {
+	if len(constructors) < 2 || constructors[0].Priority == constructors[1].Priority {
+		return nil
+	}
+
+	return constructors[0]
+}
+
+// sortByPriority orders the constructors by descending priority keeping the
+// order of constructors with equal priority.
+func sortByPriority(constructors []*constructor) {
+	sort.SliceStable(constructors, func(i, j int) bool {
+		return constructors[i].Priority > constructors[j].Priority
+	})
+}
@@ -0,0 +1,162 @@
+package injector
+
+import (
+	"context"
+	"testing"
+)
+
+type PriorityApp struct {
+	Cache  PriorityCache
+	Caches []PriorityCache
+}
+
+type PriorityCache interface {
+	Name() string
+}
+
+type PriorityRedisCache struct {
+	name string
+}
+
+func (cache *PriorityRedisCache) Name() string {
+	return cache.name
+}
+
+type PriorityMemoryCache struct {
+	name string
+}
+
+func (cache *PriorityMemoryCache) Name() string {
+	return cache.name
+}
+
+func NewPriorityRedisCache() *PriorityRedisCache {
+	return &PriorityRedisCache{name: "redis"}
+}
+
+func NewPriorityMemoryCache() *PriorityMemoryCache {
+	return &PriorityMemoryCache{name: "memory"}
+}
+
+func TestPriority(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewPriorityMemoryCache)
+	container.RegisterWithPriority(10, NewPriorityRedisCache)
+
+	app := &PriorityApp{}
+	container.Resolve(app)
+
+	if app.Cache == nil || app.Cache.Name() != "redis" {
+		t.Errorf("Cache with highest priority was not injected")
+	}
+
+	if len(app.Caches) != 2 || app.Caches[0] != app.Cache || app.Caches[1].Name() != "memory" {
+		t.Errorf("Caches were not ordered by priority")
+	}
+}
+
+func TestPriorityTie(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterWithPriority(10, NewPriorityMemoryCache, NewPriorityRedisCache)
+
+	if err := container.ResolveContext(context.Background(), &PriorityApp{}); err == nil {
+		t.Errorf("Tie of highest priority was not detected")
+	}
+
+	container = NewContainer()
+
+	container.RegisterWithPriority(10, NewPriorityMemoryCache, NewPriorityRedisCache)
+
+	app := &struct {
+		Caches []PriorityCache
+	}{}
+
+	container.Resolve(app)
+
+	if len(app.Caches) != 2 || app.Caches[0].Name() != "memory" || app.Caches[1].Name() != "redis" {
+		t.Errorf("Caches of equal priority did not keep their order")
+	}
+}
+
+type PrioritySliceApp struct {
+	All     []PriorityHandler
+	Primary PriorityHandler
+	Admin   PriorityHandler `inject:"name=admin"`
+}
+
+type PriorityHandler interface {
+	Handle() string
+}
+
+type PriorityNamedHandler struct {
+	name string
+}
+
+func (handler *PriorityNamedHandler) Handle() string {
+	return handler.name
+}
+
+func TestPrioritySingleAndSlice(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	newHandler := func(name string) func() PriorityHandler {
+		return func() PriorityHandler {
+			constructed++
+			return &PriorityNamedHandler{name: name}
+		}
+	}
+
+	container.Register(newHandler("fallback"))
+	container.RegisterWithPriority(10, newHandler("primary"))
+	container.RegisterNamed("admin", newHandler("admin"))
+
+	app := &PrioritySliceApp{}
+	container.Resolve(app)
+
+	if len(app.All) != 3 || constructed != 3 {
+		t.Fatalf("Handlers were not constructed once each")
+	}
+
+	if app.Primary.Handle() != "primary" || app.Primary != app.All[0] {
+		t.Errorf("Primary handler is not the member of the slice")
+	}
+
+	if app.Admin.Handle() != "admin" || app.Admin != app.All[2] {
+		t.Errorf("Named handler is not the member of the slice")
+	}
+}
+
+type PriorityChildApp struct {
+	Cache PriorityCache
+}
+
+func TestPriorityChild(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterWithPriority(10, NewPriorityRedisCache)
+
+	child := container.NewChild()
+	child.Register(NewPriorityMemoryCache)
+
+	app := &PriorityChildApp{}
+	child.Resolve(app)
+
+	if app.Cache == nil || app.Cache.Name() != "memory" {
+		t.Errorf("Cache of the parent with a higher priority shadowed the cache of the child")
+	}
+
+	child = container.NewChild()
+	child.Register(NewPriorityMemoryCache)
+	child.RegisterWithPriority(5, func() *PriorityRedisCache { return &PriorityRedisCache{name: "child"} })
+
+	app = &PriorityChildApp{}
+	child.Resolve(app)
+
+	if app.Cache == nil || app.Cache.Name() != "child" {
+		t.Errorf("Cache with the highest priority of the child was not injected")
+	}
+}
@@ -0,0 +1,72 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Provide works like Register but returns an error instead of panicking if a
+// constructor cannot be registered. No constructor is registered in that case.
+//
+//   if err := container.Provide(NewConfig, NewServer); err != nil {
+//     log.Fatal(err)
+//   }
+//
+// Provide, ProvideInstance, ResolveE, ResolveContext, Validate, Invoke and
+// the E variants of the other registrations and resolutions, e.g.
+// RegisterGroupE, RegisterNamedE, OverrideE, ResolveAllE or ResolveWithE,
+// return their failures as errors. The variants without E panic with the
+// same error, so a recovered value can be inspected with errors.As.
+func (container *Container) Provide(constructors ...interface{}) error {
+	var details []*constructor
+
+	for _, _constructor := range constructors {
+		parsed, err := parseConstructor(_constructor)
+
+		if err != nil {
+			return err
+		}
+
+		details = append(details, parsed)
+	}
+
+	container.addConstructors(details...)
+
+	return nil
+}
+
+// ProvideInstance works like RegisterInstance but returns an error instead of
+// panicking if an instance is nil. No instance is registered in that case.
+func (container *Container) ProvideInstance(instances ...interface{}) error {
+	for i, instance := range instances {
+		if !reflect.ValueOf(instance).IsValid() {
+			return fmt.Errorf("Instance at position %d must not be nil", i)
+		}
+	}
+
+	container.RegisterInstance(instances...)
+
+	return nil
+}
+
+// ResolveE works like Resolve but returns the error Resolve would panic with.
+// The root is left untouched on error. Missing or ambiguous dependencies and
+// dependency cycles are reported as *MissingDependencyError,
+// *AmbiguousDependencyError and *CycleError, carrying the type and the chain
+// of constructors involved.
+//
+//   var app App
+//
+//   if err := container.ResolveE(&app); err != nil {
+//     var missing *injector.MissingDependencyError
+//
+//     if errors.As(err, &missing) {
+//       log.Fatalf("Nothing provides %s", missing.Type)
+//     }
+//
+//     log.Fatal(err)
+//   }
+func (container *Container) ResolveE(root interface{}) error {
+	return container.resolveOwned(context.Background(), []interface{}{root}, nil)
+}
@@ -0,0 +1,335 @@
+package injector
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ProvideStore interface {
+	Load() string
+}
+
+type ProvideMemoryStore struct{}
+
+func (*ProvideMemoryStore) Load() string {
+	return "memory"
+}
+
+type ProvideFileStore struct{}
+
+func (*ProvideFileStore) Load() string {
+	return "file"
+}
+
+type ProvideA struct{}
+
+type ProvideB struct{}
+
+type ProvideApp struct {
+	Store ProvideStore
+}
+
+type ProvideCycleApp struct {
+	A *ProvideA
+}
+
+func TestProvide(t *testing.T) {
+	container := NewContainer()
+
+	store := func() ProvideStore { return &ProvideMemoryStore{} }
+
+	if err := container.Provide(store); err != nil {
+		t.Fatalf("Constructor could not be provided: %s", err)
+	}
+
+	var app ProvideApp
+
+	if err := container.ResolveE(&app); err != nil || app.Store == nil || app.Store.Load() != "memory" {
+		t.Errorf("ProvideApp could not be resolved: %v", err)
+	}
+
+	if err := container.Provide(func() *ProvideA { return &ProvideA{} }, "invalid"); err == nil {
+		t.Errorf("Invalid constructor was provided")
+	}
+
+	if len(container.findConstructors(reflect.TypeOf(&ProvideA{}))) != 0 {
+		t.Errorf("Valid constructor was provided along with an invalid one")
+	}
+
+	if err := container.RegisterGroupE("stores", func() *ProvideA { return &ProvideA{} }, "invalid"); err == nil {
+		t.Errorf("Invalid group member was registered")
+	}
+
+	if len(container.findConstructors(reflect.TypeOf(&ProvideA{}))) != 0 {
+		t.Errorf("Valid group member was registered along with an invalid one")
+	}
+}
+
+func TestPanicErrors(t *testing.T) {
+	run := func(name string, call func()) {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				recovered := recover()
+
+				if recovered == nil {
+					t.Fatal("Call did not panic")
+				}
+
+				if _, ok := recovered.(error); !ok {
+					t.Errorf("Panic '%v' is not an error", recovered)
+				}
+			}()
+
+			call()
+		})
+	}
+
+	run("register", func() { NewContainer().Register("invalid") })
+	run("register group", func() { NewContainer().RegisterGroup("stores", "invalid") })
+	run("register instance", func() { NewContainer().RegisterInstance(nil) })
+	run("resolve", func() { NewContainer().Resolve(&ProvideApp{}) })
+	run("keyed", func() { NewContainer().RegisterKeyed(func() *ProvideA { return &ProvideA{} }) })
+}
+
+func TestPanicFreeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      func(*Container) error
+		expected string
+		typed    interface{}
+	}{
+		{
+			name: "constructor is not a function",
+			run: func(container *Container) error {
+				return container.Provide("invalid")
+			},
+			expected: "Constructor 'string' is not a function",
+		},
+		{
+			name: "constructor without return value",
+			run: func(container *Container) error {
+				return container.Provide(func() {})
+			},
+			expected: "Constructor 'func()' must have single return value",
+		},
+		{
+			name: "constructor with unsupported parameter",
+			run: func(container *Container) error {
+				return container.Provide(func(*ProvideStore) *ProvideA { return nil })
+			},
+			expected: "Unsupported parameter kind '*injector.ProvideStore'",
+		},
+		{
+			name: "nil instance",
+			run: func(container *Container) error {
+				return container.ProvideInstance(&ProvideA{}, nil)
+			},
+			expected: "Instance at position 1 must not be nil",
+		},
+		{
+			name: "invalid root",
+			run: func(container *Container) error {
+				return container.ResolveE(ProvideApp{})
+			},
+			expected: "Root 'injector.ProvideApp' must be a non-nil pointer to a struct",
+		},
+		{
+			name: "nil root",
+			run: func(container *Container) error {
+				return container.ResolveE((*ProvideApp)(nil))
+			},
+			expected: "Root '*injector.ProvideApp' must be a non-nil pointer to a struct",
+		},
+		{
+			name: "missing dependency",
+			run: func(container *Container) error {
+				return container.ResolveE(&ProvideApp{})
+			},
+			expected: "No constructor defined for type 'injector.ProvideStore'",
+			typed:    new(*MissingDependencyError),
+		},
+		{
+			name: "ambiguous dependency",
+			run: func(container *Container) error {
+				container.Provide(
+					func() *ProvideMemoryStore { return &ProvideMemoryStore{} },
+					func() *ProvideFileStore { return &ProvideFileStore{} },
+				)
+
+				return container.ResolveE(&ProvideApp{})
+			},
+			expected: "Ambiguity detected for type 'injector.ProvideStore'",
+			typed:    new(*AmbiguousDependencyError),
+		},
+		{
+			name: "dependency cycle",
+			run: func(container *Container) error {
+				container.Provide(
+					func(*ProvideB) *ProvideA { return &ProvideA{} },
+					func(*ProvideA) *ProvideB { return &ProvideB{} },
+				)
+
+				return container.ResolveE(&ProvideCycleApp{})
+			},
+			expected: "Cycle detected",
+			typed:    new(*CycleError),
+		},
+		{
+			name: "validated dependency cycle",
+			run: func(container *Container) error {
+				container.Provide(
+					func(*ProvideB) *ProvideA { return &ProvideA{} },
+					func(*ProvideA) *ProvideB { return &ProvideB{} },
+				)
+
+				return container.Validate(&ProvideCycleApp{})
+			},
+			expected: "Cycle detected",
+			typed:    new(*CycleError),
+		},
+		{
+			name: "ambiguous invoke parameter",
+			run: func(container *Container) error {
+				container.Provide(
+					func() *ProvideMemoryStore { return &ProvideMemoryStore{} },
+					func() *ProvideFileStore { return &ProvideFileStore{} },
+				)
+
+				return container.Invoke(func(ProvideStore) {})
+			},
+			expected: "Ambiguity detected for type 'injector.ProvideStore'",
+			typed:    new(*AmbiguousDependencyError),
+		},
+		{
+			name: "named constructor is not a function",
+			run: func(container *Container) error {
+				return container.RegisterNamedE("store", "invalid")
+			},
+			expected: "Constructor 'string' is not a function",
+		},
+		{
+			name: "group member is not a function",
+			run: func(container *Container) error {
+				return container.RegisterGroupE("stores", func() *ProvideA { return &ProvideA{} }, "invalid")
+			},
+			expected: "Constructor 'string' is not a function",
+		},
+		{
+			name: "binding not implemented",
+			run: func(container *Container) error {
+				return container.RegisterAsE((*ProvideStore)(nil), func() *ProvideA { return &ProvideA{} })
+			},
+			expected: "Type '*injector.ProvideA' returned by constructor 'func() *injector.ProvideA' does not implement 'injector.ProvideStore'",
+		},
+		{
+			name: "keyed constructor without key",
+			run: func(container *Container) error {
+				return container.RegisterKeyedE(func() *ProvideA { return &ProvideA{} })
+			},
+			expected: "Keyed constructor 'func() *injector.ProvideA' must take the key as first parameter",
+		},
+		{
+			name: "invalid tag",
+			run: func(container *Container) error {
+				return container.RegisterTaggedE(func() *ProvideA { return &ProvideA{} }, "invalid")
+			},
+			expected: "Invalid tag 'invalid' of constructor 'func() *injector.ProvideA'",
+		},
+		{
+			name: "more tags than parameters",
+			run: func(container *Container) error {
+				return container.RegisterWithTagsE(func() *ProvideA { return &ProvideA{} }, "names:stores")
+			},
+			expected: "Constructor 'func() *injector.ProvideA' has fewer parameters than tags",
+		},
+		{
+			name: "override without constructor",
+			run: func(container *Container) error {
+				return container.OverrideE(func() *ProvideA { return &ProvideA{} })
+			},
+			expected: "No constructor defined for overridden type '*injector.ProvideA'",
+		},
+		{
+			name: "merge conflict",
+			run: func(container *Container) error {
+				other := NewContainer()
+
+				container.Provide(func() *ProvideA { return &ProvideA{} })
+				other.Provide(func() *ProvideA { return &ProvideA{} })
+
+				_, err := container.MergeE(other)
+				return err
+			},
+			expected: "Type '*injector.ProvideA' of constructor 'func() *injector.ProvideA' is registered with more than one of the merged containers",
+		},
+		{
+			name: "env config is not a pointer",
+			run: func(container *Container) error {
+				return container.BindEnvE(ProvideApp{})
+			},
+			expected: "Config 'injector.ProvideApp' must be a pointer to a struct",
+		},
+		{
+			name: "missing dependency of several roots",
+			run: func(container *Container) error {
+				return container.ResolveAllE(&ProvideCycleApp{}, &ProvideApp{})
+			},
+			expected: "No constructor defined for type '*injector.ProvideA'",
+			typed:    new(*MissingDependencyError),
+		},
+		{
+			name: "override not assignable",
+			run: func(container *Container) error {
+				return container.ResolveWithE(&ProvideApp{}, map[reflect.Type]interface{}{
+					reflect.TypeOf((*ProvideStore)(nil)).Elem(): &ProvideA{},
+				})
+			},
+			expected: "Override '*injector.ProvideA' is not assignable to type 'injector.ProvideStore'",
+		},
+		{
+			name: "seed not assignable",
+			run: func(container *Container) error {
+				return container.ResolveSeededE(&ProvideApp{}, map[reflect.Type]interface{}{
+					reflect.TypeOf((*ProvideStore)(nil)).Elem(): &ProvideA{},
+				})
+			},
+			expected: "Override '*injector.ProvideA' is not assignable to type 'injector.ProvideStore'",
+		},
+		{
+			name: "reused root with unexported field",
+			run: func(container *Container) error {
+				return container.ResolveReusingE(&ProvideApp{}, &struct{ store ProvideStore }{})
+			},
+			expected: "Field 'store' of root type 'struct { store injector.ProvideStore }' is unexported and cannot be reused",
+		},
+		{
+			name: "invoke of invalid function",
+			run: func(container *Container) error {
+				return container.Invoke(func() int { return 0 })
+			},
+			expected: "Function 'func() int' must be a function returning nothing or an error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.Errorf("Failure panicked instead of returning an error: %v", recovered)
+				}
+			}()
+
+			err := test.run(NewContainer())
+
+			if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+				t.Errorf("Expected error starting with %q, got %v", test.expected, err)
+			}
+
+			if test.typed != nil && !errors.As(err, test.typed) {
+				t.Errorf("Error %v is not of type '%T'", err, test.typed)
+			}
+		})
+	}
+}
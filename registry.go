@@ -0,0 +1,62 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// Registry looks up named implementations of T when they are needed instead
+// of when the registry is injected.
+//
+//   func NewDispatcher(handlers *injector.Registry[Handler]) *Dispatcher {…}
+//
+//   handler, err := dispatcher.handlers.Get(command)
+//
+// Values retrieved from the registry are singletons shared with the
+// resolution that injected the registry.
+type Registry[T any] struct {
+	resolver *resolver
+}
+
+func (registry *Registry[T]) synthesize(_resolver *resolver) error {
+	registry.resolver = _resolver
+	return nil
+}
+
+// Get returns the implementation of T registered under the given name.
+func (registry *Registry[T]) Get(name string) (T, error) {
+	var result T
+
+	_type := reflect.TypeOf((*T)(nil)).Elem()
+
+	_resolver := registry.resolver
+
+	_resolver.Lock.Lock()
+	defer _resolver.Lock.Unlock()
+
+	if err := _resolver.Container.checkDisposed(); err != nil {
+		return result, err
+	}
+
+	constructor, err := _resolver.Container.findNamedConstructor(name, _type)
+
+	if err != nil {
+		return result, err
+	}
+
+	value, err := _resolver.constructOwned(func() (reflect.Value, error) {
+		return _resolver.provide(constructor, _type)
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	reflect.ValueOf(&result).Elem().Set(value)
+
+	return result, nil
+}
+
+// Names returns the names of all implementations of T in registration order.
+func (registry *Registry[T]) Names() []string {
+	return registry.resolver.Container.names(reflect.TypeOf((*T)(nil)).Elem())
+}
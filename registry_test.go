@@ -0,0 +1,121 @@
+package injector
+
+import (
+	"testing"
+)
+
+type RegistryApp struct {
+	Dispatcher *RegistryDispatcher
+}
+
+type RegistryDispatcher struct {
+	handlers *Registry[RegistryHandler]
+}
+
+type RegistryHandler interface {
+	Handle() string
+}
+
+type RegistryStartHandler struct {
+}
+
+func (handler *RegistryStartHandler) Handle() string {
+	return "start"
+}
+
+type RegistryStopHandler struct {
+}
+
+func (handler *RegistryStopHandler) Handle() string {
+	return "stop"
+}
+
+func NewRegistryDispatcher(handlers *Registry[RegistryHandler]) *RegistryDispatcher {
+	return &RegistryDispatcher{
+		handlers: handlers,
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	container := NewContainer()
+
+	constructed := 0
+
+	container.Register(NewRegistryDispatcher)
+	container.RegisterNamed("start", func() *RegistryStartHandler {
+		constructed++
+		return &RegistryStartHandler{}
+	})
+	container.RegisterNamed("stop", func() *RegistryStopHandler {
+		return &RegistryStopHandler{}
+	})
+
+	app := &RegistryApp{}
+	container.Resolve(app)
+
+	if constructed != 0 {
+		t.Errorf("Handler was constructed before it was needed")
+	}
+
+	handler, err := app.Dispatcher.handlers.Get("start")
+
+	if err != nil || handler.Handle() != "start" {
+		t.Errorf("Handler could not be resolved")
+	}
+
+	other, _ := app.Dispatcher.handlers.Get("start")
+
+	if other != handler || constructed != 1 {
+		t.Errorf("Handler is not a singleton")
+	}
+
+	if _, err := app.Dispatcher.handlers.Get("restart"); err == nil {
+		t.Errorf("Unknown handler was not detected")
+	}
+
+	names := app.Dispatcher.handlers.Names()
+
+	if len(names) != 2 || names[0] != "start" || names[1] != "stop" {
+		t.Errorf("Unexpected names %v", names)
+	}
+}
+
+type RegistryConnection struct {
+}
+
+type RegistryReportHandler struct {
+	connection *RegistryConnection
+}
+
+func (handler *RegistryReportHandler) Handle() string {
+	return "report"
+}
+
+func TestRegistryDispose(t *testing.T) {
+	container := NewContainer()
+
+	var closed []string
+
+	container.Register(NewRegistryDispatcher)
+	container.Register(func() (*RegistryConnection, func()) {
+		return &RegistryConnection{}, func() { closed = append(closed, "connection") }
+	})
+	container.RegisterNamed("report", func(connection *RegistryConnection) (*RegistryReportHandler, func()) {
+		return &RegistryReportHandler{connection: connection}, func() { closed = append(closed, "handler") }
+	})
+
+	app := &RegistryApp{}
+	container.Resolve(app)
+
+	if _, err := app.Dispatcher.handlers.Get("report"); err != nil {
+		t.Fatalf("Handler could not be resolved: %s", err)
+	}
+
+	if err := container.Dispose(); err != nil {
+		t.Fatalf("Container could not be disposed: %s", err)
+	}
+
+	if len(closed) != 2 || closed[0] != "handler" || closed[1] != "connection" {
+		t.Errorf("Unexpected cleanups run by Dispose %v", closed)
+	}
+}
@@ -0,0 +1,69 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// RegisterWithRetry registers a constructor returning an error as second value
+// that is invoked again if it fails, e.g. while waiting for a database that
+// starts asynchronously. The constructor is invoked at most the given number
+// of times, waiting for the backoff between the attempts. The backoff doubles
+// after every attempt.
+//
+//   container.RegisterWithRetry(NewDB, 5, 100*time.Millisecond)
+//
+// Every error returned by the constructor is considered transient. Canceling
+// the context of the resolution stops waiting for the next attempt.
+func (container *Container) RegisterWithRetry(_constructor interface{}, attempts int, backoff time.Duration) {
+	details := newConstructor(_constructor)
+
+	if !details.ReturnsError {
+		panic(fmt.Errorf("Constructor '%s' registered with retry must return an error as second value", details.Function.Type()))
+	}
+
+	if attempts < 1 {
+		panic(fmt.Errorf("Constructor '%s' must be attempted at least once", details.Function.Type()))
+	}
+
+	details.Attempts = attempts
+	details.Backoff = backoff
+
+	container.addConstructors(details)
+}
+
+// call invokes the constructor with the arguments and retries it as
+// configured until it returns no error.
+func (_resolver *resolver) call(constructor *constructor, arguments []reflect.Value) ([]reflect.Value, error) {
+	backoff := constructor.Backoff
+
+	for attempt := 1; ; attempt++ {
+		results := constructor.Function.Call(arguments)
+
+		if !constructor.ReturnsError || results[1].IsNil() {
+			return results, nil
+		}
+
+		err := results[1].Interface().(error)
+
+		if attempt >= constructor.Attempts {
+			if attempt > 1 {
+				return nil, fmt.Errorf("Constructor '%s' failed after %d attempts: %w", constructor.Function.Type(), attempt, err)
+			}
+
+			return nil, fmt.Errorf("Constructor '%s' failed: %w", constructor.Function.Type(), err)
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-_resolver.Context.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("Resolution aborted while retrying constructor '%s': %w", constructor.Function.Type(), _resolver.Context.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
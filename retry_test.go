@@ -0,0 +1,87 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type RetryApp struct {
+	Database *RetryDatabase
+}
+
+type RetryDatabase struct {
+	attempts int
+}
+
+var errRetryUnavailable = errors.New("database unavailable")
+
+func TestRetry(t *testing.T) {
+	container := NewContainer()
+
+	attempts := 0
+
+	container.RegisterWithRetry(func() (*RetryDatabase, error) {
+		attempts++
+
+		if attempts < 3 {
+			return nil, errRetryUnavailable
+		}
+
+		return &RetryDatabase{attempts: attempts}, nil
+	}, 3, time.Millisecond)
+
+	app := &RetryApp{}
+	container.Resolve(app)
+
+	if app.Database == nil || app.Database.attempts != 3 {
+		t.Errorf("Database could not be resolved after retrying")
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterWithRetry(func() (*RetryDatabase, error) {
+		return nil, errRetryUnavailable
+	}, 2, time.Millisecond)
+
+	err := container.ResolveContext(context.Background(), &RetryApp{})
+
+	if !errors.Is(err, errRetryUnavailable) || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("Failing constructor was not reported: %v", err)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	container := NewContainer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	container.RegisterWithRetry(func() (*RetryDatabase, error) {
+		cancel()
+		return nil, errRetryUnavailable
+	}, 3, time.Hour)
+
+	err := container.ResolveContext(ctx, &RetryApp{})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Cancellation was not respected while retrying: %v", err)
+	}
+}
+
+func TestConstructorError(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() (*RetryDatabase, error) {
+		return nil, errRetryUnavailable
+	})
+
+	err := container.ResolveContext(context.Background(), &RetryApp{})
+
+	if !errors.Is(err, errRetryUnavailable) {
+		t.Errorf("Error of constructor was not returned: %v", err)
+	}
+}
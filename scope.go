@@ -0,0 +1,122 @@
+package injector
+
+// NewChild creates a child container that sees all constructors of its parent
+// in addition to its own. Constructors registered with the child do not
+// affect the parent.
+//
+// Slices are filled with the values of the parent first, followed by the
+// values of the child. A single value is taken from the child if the child
+// defines one, shadowing the constructors of the parent.
+func (container *Container) NewChild() *Container {
+	return &Container{
+		parent: container,
+
+		diagnosticHandler: container.diagnosticHandler,
+		keyByType:         container.keyByType,
+		filterByType:      container.filterByType,
+		depthOrdered:      container.depthOrdered,
+
+		explicitInterfaces: container.explicitInterfaces,
+		clock:              container.clock,
+		strictPointers:     container.strictPointers,
+		verboseErrors:      container.verboseErrors,
+		cycleHandler:       container.cycleHandler,
+		mode:               container.mode,
+		zeroAllocation:     container.zeroAllocation,
+		strictExports:      container.strictExports,
+	}
+}
+
+// nearestConstructors returns the constructors registered with the nearest
+// container, walking from the container up to the root container. A single
+// value is taken from the nearest container defining one, so a child shadows
+// the constructors of its ancestors.
+func (container *Container) nearestConstructors(constructors []*constructor) []*constructor {
+	for current := container; current != nil; current = current.parent {
+		var nearest []*constructor
+
+		for _, constructor := range constructors {
+			for _, own := range current.constructors {
+				if constructor == own {
+					nearest = append(nearest, constructor)
+				}
+			}
+		}
+
+		if len(nearest) > 0 {
+			return nearest
+		}
+	}
+
+	return constructors
+}
+
+// allConstructors returns the constructors of the container and all its
+// ancestors, starting with the root container.
+func (container *Container) allConstructors() []*constructor {
+	if container.parent == nil {
+		return container.constructors
+	}
+
+	var constructors []*constructor
+
+	constructors = append(constructors, container.parent.allConstructors()...)
+	constructors = append(constructors, container.constructors...)
+
+	return constructors
+}
+
+// Scope is a lightweight child container injected into constructors that
+// need to resolve operation-local values. Every injection receives a fresh
+// scope whose lifetime is bound to the constructed value holding it.
+//
+//   func NewJobRunner(scope *injector.Scope) *JobRunner {…}
+//
+//   scope.RegisterInstance(job)
+//   err := scope.Resolve(&worker)
+//
+// Resolutions of a scope reuse the values the surrounding resolution has
+// constructed so far, values of constructors registered with the scope are
+// local to each resolution of the scope.
+type Scope struct {
+	container *Container
+	parent    *resolver
+}
+
+func (scope *Scope) synthesize(_resolver *resolver) error {
+	scope.container = _resolver.Container.NewChild()
+	scope.parent = _resolver
+	return nil
+}
+
+// Register registers constructors with the scope like Container.Register.
+func (scope *Scope) Register(constructors ...interface{}) {
+	scope.container.Register(constructors...)
+}
+
+// RegisterInstance registers instances with the scope like
+// Container.RegisterInstance.
+func (scope *Scope) RegisterInstance(instances ...interface{}) {
+	scope.container.RegisterInstance(instances...)
+}
+
+// Resolve works like Container.ResolveContext using the context of the
+// surrounding resolution.
+func (scope *Scope) Resolve(root interface{}) error {
+	prepare := func(_resolver *resolver) error {
+		// Lazy values and registries may construct values of the surrounding
+		// resolution concurrently. A constructor they invoke can resolve its
+		// scope since the goroutine holding the lock can lock it again.
+		scope.parent.Lock.Lock()
+		defer scope.parent.Lock.Unlock()
+
+		for constructor, value := range scope.parent.ValueByConstructor {
+			_resolver.ValueByConstructor[constructor] = value
+		}
+
+		return nil
+	}
+
+	err := scope.container.resolveOwned(scope.parent.Context, []interface{}{root}, prepare)
+	return err
+}
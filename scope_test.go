@@ -0,0 +1,293 @@
+package injector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type ChildApp struct {
+	Router *ChildRouter
+}
+
+type ChildRouter struct {
+	handlers []ChildHandler
+}
+
+type ChildHandler interface {
+	Handle() string
+}
+
+type ChildParentHandler struct {
+}
+
+func (handler *ChildParentHandler) Handle() string {
+	return "parent"
+}
+
+type ChildChildHandler struct {
+}
+
+func (handler *ChildChildHandler) Handle() string {
+	return "child"
+}
+
+func NewChildRouter(handlers []ChildHandler) *ChildRouter {
+	return &ChildRouter{
+		handlers: handlers,
+	}
+}
+
+func NewChildParentHandler() *ChildParentHandler {
+	return &ChildParentHandler{}
+}
+
+func NewChildChildHandler() *ChildChildHandler {
+	return &ChildChildHandler{}
+}
+
+func TestChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(NewChildRouter, NewChildParentHandler)
+
+	child := parent.NewChild()
+	child.Register(NewChildChildHandler)
+
+	app := &ChildApp{}
+	child.Resolve(app)
+
+	handlers := app.Router.handlers
+
+	if len(handlers) != 2 || handlers[0].Handle() != "parent" || handlers[1].Handle() != "child" {
+		t.Errorf("Router could not be resolved")
+	}
+
+	app = &ChildApp{}
+	parent.Resolve(app)
+
+	if len(app.Router.handlers) != 1 {
+		t.Errorf("Child constructors leaked into parent")
+	}
+}
+
+type ChildSingleApp struct {
+	Handler ChildHandler
+}
+
+func TestChildShadowing(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(NewChildParentHandler)
+
+	child := parent.NewChild()
+	child.Register(NewChildChildHandler)
+
+	app := &ChildSingleApp{}
+	child.Resolve(app)
+
+	if app.Handler.Handle() != "child" {
+		t.Errorf("Handler of child did not shadow handler of parent")
+	}
+
+	app = &ChildSingleApp{}
+	parent.NewChild().Resolve(app)
+
+	if app.Handler.Handle() != "parent" {
+		t.Errorf("Handler of parent could not be resolved")
+	}
+
+	parent = NewContainer()
+	parent.RegisterInstance(&ChildParentHandler{})
+
+	child = parent.NewChild()
+	child.RegisterInstance(&ChildChildHandler{})
+
+	app = &ChildSingleApp{}
+	child.Resolve(app)
+
+	if app.Handler.Handle() != "child" {
+		t.Errorf("Instance of child did not shadow instance of parent")
+	}
+}
+
+func TestChildInterface(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(NewChildParentHandler)
+
+	child := parent.NewChild()
+	child.Register(NewChildRouter)
+
+	app := &struct {
+		Handler ChildHandler
+		Router  *ChildRouter
+	}{}
+
+	child.Resolve(app)
+
+	if app.Handler.Handle() != "parent" {
+		t.Errorf("Handler of parent could not be resolved in child")
+	}
+
+	if len(app.Router.handlers) != 1 || app.Router.handlers[0] != app.Handler {
+		t.Errorf("Handler of parent was not shared in child")
+	}
+}
+
+type ScopeApp struct {
+	Runner *ScopeRunner
+}
+
+type ScopeRunner struct {
+	scope  *Scope
+	logger *ScopeLogger
+}
+
+type ScopeLogger struct {
+	prefix string
+}
+
+type ScopeJob struct {
+	id int
+}
+
+type ScopeWorker struct {
+	Job    *ScopeJob
+	Logger *ScopeLogger
+}
+
+func NewScopeRunner(scope *Scope, logger *ScopeLogger) *ScopeRunner {
+	return &ScopeRunner{
+		scope:  scope,
+		logger: logger,
+	}
+}
+
+func (runner *ScopeRunner) Run(id int) (*ScopeWorker, error) {
+	runner.scope.RegisterInstance(&ScopeJob{id: id})
+
+	worker := &ScopeWorker{}
+	err := runner.scope.Resolve(worker)
+
+	return worker, err
+}
+
+func TestScope(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewScopeRunner, func() *ScopeLogger {
+		return &ScopeLogger{prefix: "runner"}
+	})
+
+	app := &ScopeApp{}
+	container.Resolve(app)
+
+	worker, err := app.Runner.Run(1)
+
+	if err != nil {
+		t.Fatalf("Worker could not be resolved: %s", err)
+	}
+
+	if worker.Job.id != 1 {
+		t.Errorf("Scoped job was not injected")
+	}
+
+	if worker.Logger != app.Runner.logger {
+		t.Errorf("Logger was not shared with the scope")
+	}
+
+	if err := container.ResolveContext(context.Background(), &ScopeWorker{}); err == nil {
+		t.Errorf("Scoped job leaked into the container")
+	}
+}
+
+type ScopeLazyApp struct {
+	Runner  *ScopeRunner
+	Reports *Lazy[*ScopeReports]
+}
+
+type ScopeReports struct {
+}
+
+type ScopeLoggerRoot struct {
+	Logger *ScopeLogger
+}
+
+// TestScopeLazy is meant to be run with -race.
+func TestScopeLazy(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewScopeRunner, func() *ScopeLogger {
+		return &ScopeLogger{prefix: "runner"}
+	}, func() *ScopeReports {
+		return &ScopeReports{}
+	})
+
+	app := &ScopeLazyApp{}
+	container.Resolve(app)
+
+	done := make(chan error)
+
+	go func() {
+		_, err := app.Reports.Get()
+		done <- err
+	}()
+
+	for i := 0; i < 10; i++ {
+		root := &ScopeLoggerRoot{}
+
+		if err := app.Runner.scope.Resolve(root); err != nil || root.Logger != app.Runner.logger {
+			t.Errorf("Logger was not shared with the scope: %v", err)
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Reports could not be constructed: %s", err)
+	}
+}
+
+type ScopeBatchApp struct {
+	Logger *ScopeLogger
+	Batch  *Lazy[*ScopeBatch]
+}
+
+type ScopeBatch struct {
+	worker *ScopeWorker
+}
+
+func TestScopeInLazyConstructor(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *ScopeLogger {
+		return &ScopeLogger{prefix: "batch"}
+	}, func(scope *Scope) (*ScopeBatch, error) {
+		scope.RegisterInstance(&ScopeJob{id: 7})
+
+		batch := &ScopeBatch{worker: &ScopeWorker{}}
+		err := scope.Resolve(batch.worker)
+
+		return batch, err
+	})
+
+	app := &ScopeBatchApp{}
+	container.Resolve(app)
+
+	done := make(chan error, 1)
+
+	go func() {
+		batch, err := app.Batch.Get()
+
+		if err == nil && (batch.worker.Job.id != 7 || batch.worker.Logger != app.Logger) {
+			t.Errorf("Scope of the lazily constructed batch did not resolve its worker")
+		}
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Batch could not be constructed: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Resolving a scope inside a lazily constructed value deadlocked")
+	}
+}
@@ -0,0 +1,266 @@
+package injector
+
+import (
+	"reflect"
+)
+
+type singleton struct {
+	Value        reflect.Value
+	Depth        int
+	Requirements []*constructor
+}
+
+// cacheSingletons keeps the values the constructors produced for the
+// resolver, so later resolutions of the container and its children reuse
+// them instead of invoking the constructors again. Values of transient
+// constructors are never cached, values reused from the singletons are
+// already cached.
+func (container *Container) cacheSingletons(_resolver *resolver, constructors []*constructor) {
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	container.dropChangedByAncestors()
+
+	if container.singletons == nil {
+		container.singletons = make(map[*constructor]singleton)
+	}
+
+	for _, constructor := range constructors {
+		if _resolver.transient(constructor) || _resolver.Placeholder[constructor] || _resolver.Reused[constructor] {
+			continue
+		}
+
+		container.singletons[constructor] = singleton{
+			Value:        _resolver.ValueByConstructor[constructor],
+			Depth:        _resolver.Depth[constructor],
+			Requirements: _resolver.Requirements[constructor],
+		}
+	}
+
+	for _type, pointer := range _resolver.AddressedValues {
+		if container.addressed == nil {
+			container.addressed = make(map[reflect.Type]reflect.Value)
+		}
+
+		container.addressed[_type] = pointer
+	}
+}
+
+// lookupSingleton returns the value cached for the constructor by the
+// container or the nearest ancestor having one. The value of an ancestor is
+// only shared if the container inherits it.
+func (container *Container) lookupSingleton(constructor *constructor) (singleton, bool) {
+	for current := container; current != nil; current = current.parent {
+		current.lock.Lock()
+
+		// The values may depend on constructors an ancestor registered since
+		current.dropChangedByAncestors()
+
+		cached, ok := current.singletons[constructor]
+		shared := ok && (current == container || container.inherits(current, constructor))
+		current.lock.Unlock()
+
+		if shared {
+			return cached, true
+		}
+	}
+
+	return singleton{}, false
+}
+
+// addressedValue returns the pointer the resolution or, if it reuses the
+// singletons, an earlier resolution of the container injected for a pointer
+// type without a constructor of its own, see convertPointer and
+// allocateZero. The pointers of ancestors are not shared, a child points to
+// copies of its own.
+func (_resolver *resolver) addressedValue(_type reflect.Type) (reflect.Value, bool) {
+	if pointer, ok := _resolver.AddressedValues[_type]; ok || !_resolver.Singletons {
+		return pointer, ok
+	}
+
+	container := _resolver.Container
+
+	container.lock.Lock()
+	container.dropChangedByAncestors()
+	pointer, ok := container.addressed[_type]
+	container.lock.Unlock()
+
+	if ok {
+		_resolver.AddressedValues[_type] = pointer
+	}
+
+	return pointer, ok
+}
+
+// inherits reports whether the value the ancestor cached for the constructor
+// would be the same if the container constructed it. It is not if the
+// constructor or one it requires depends on a synthesized type, which is bound
+// to the container resolving it, or on a type the container or a container in
+// between registers a constructor for, which shadows the value of the ancestor
+// or adds to its slice. The lock of the ancestor must be held.
+func (container *Container) inherits(ancestor *Container, shared *constructor) bool {
+	visited := make(map[*constructor]bool)
+	pending := []*constructor{shared}
+
+	for len(pending) > 0 {
+		required := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if visited[required] {
+			continue
+		}
+
+		visited[required] = true
+
+		for _, param := range required.Parameters {
+			_type := innerType(param)
+
+			if _type.Kind() == reflect.Ptr && _type.Implements(synthesizedType) {
+				return false
+			}
+
+			for current := container; current != ancestor; current = current.parent {
+				for _, own := range current.constructors {
+					if own.ReturnType.AssignableTo(_type) {
+						return false
+					}
+				}
+			}
+		}
+
+		pending = append(pending, ancestor.singletons[required].Requirements...)
+	}
+
+	return true
+}
+
+// reuseSingleton takes the value of the constructor and of the constructors
+// it requires from the singletons of the container or its ancestors. It
+// reports false if none of them has a value for it.
+func (_resolver *resolver) reuseSingleton(constructor *constructor) (reflect.Value, bool) {
+	if _resolver.transient(constructor) {
+		return reflect.Value{}, false
+	}
+
+	cached, ok := _resolver.Container.lookupSingleton(constructor)
+
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	// Take the requirements first to keep dependencies before their dependents
+	for _, requirement := range cached.Requirements {
+		_resolver.constructorInvoked(requirement)
+	}
+
+	_resolver.ValueByConstructor[constructor] = cached.Value
+	_resolver.Reused[constructor] = true
+	_resolver.Depth[constructor] = cached.Depth
+	_resolver.Requirements[constructor] = cached.Requirements
+	_resolver.InvokedConstructors = append(_resolver.InvokedConstructors, constructor)
+
+	return cached.Value, true
+}
+
+// dropSingletons drops the singletons of the container that may have been
+// constructed differently without the changed constructors, e.g. because a
+// registration adds a constructor shadowing one of their dependencies or
+// adding to a slice they received. Unrelated singletons are kept. The
+// singletons of its children are dropped once they are looked up.
+func (container *Container) dropSingletons(changed ...*constructor) {
+	container.lock.Lock()
+	defer container.lock.Unlock()
+
+	container.changes = append(container.changes, changed...)
+	container.dropAffected(changed)
+
+	// The resolver would keep activating the dropped values
+	container.activationResolver = nil
+}
+
+// dropChangedByAncestors drops the singletons of the container affected by
+// the constructors its ancestors changed since the last call. The lock of the
+// container must be held.
+func (container *Container) dropChangedByAncestors() {
+	for ancestor := container.parent; ancestor != nil; ancestor = ancestor.parent {
+		ancestor.lock.Lock()
+		changed := ancestor.changes[container.seenChanges[ancestor]:]
+		ancestor.lock.Unlock()
+
+		if len(changed) == 0 {
+			continue
+		}
+
+		if container.seenChanges == nil {
+			container.seenChanges = make(map[*Container]int)
+		}
+
+		container.seenChanges[ancestor] += len(changed)
+		container.dropAffected(changed)
+		container.activationResolver = nil
+	}
+}
+
+// dropAffected drops the singletons of the changed constructors and of every
+// constructor depending on a type one of them returns, directly or through
+// other constructors. The lock of the container must be held.
+func (container *Container) dropAffected(changed []*constructor) {
+	if len(container.singletons) == 0 && len(container.addressed) == 0 || len(changed) == 0 {
+		return
+	}
+
+	affected := make(map[*constructor]bool)
+
+	var types []reflect.Type
+
+	for _, constructor := range changed {
+		affected[constructor] = true
+		types = append(types, constructor.ReturnType)
+	}
+
+	constructors := container.allConstructors()
+
+	for len(types) > 0 {
+		_type := types[len(types)-1]
+		types = types[:len(types)-1]
+
+		for _, constructor := range constructors {
+			if !affected[constructor] && constructor.dependsOn(_type) {
+				affected[constructor] = true
+				types = append(types, constructor.ReturnType)
+			}
+		}
+
+		// Pointers to copies of a value or to zero structs
+		for pointerType := range container.addressed {
+			if _type.AssignableTo(pointerType) || _type.AssignableTo(pointerType.Elem()) {
+				delete(container.addressed, pointerType)
+			}
+		}
+	}
+
+	for constructor := range affected {
+		delete(container.singletons, constructor)
+	}
+}
+
+// dependsOn reports whether a value of the type can be injected into one of
+// the parameters of the constructor, alone, as a slice member or through a
+// pointer to it.
+func (constructor *constructor) dependsOn(_type reflect.Type) bool {
+	for _, param := range constructor.Parameters {
+		param = innerType(param)
+
+		if _type.AssignableTo(param) || (param.Kind() == reflect.Ptr && _type.AssignableTo(param.Elem())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isolate keeps a resolution from sharing the singletons of the container.
+func isolate(_resolver *resolver) error {
+	_resolver.Singletons = false
+	return nil
+}
@@ -0,0 +1,295 @@
+package injector
+
+import (
+	"reflect"
+	"testing"
+)
+
+type SingletonPool struct {
+	id int
+}
+
+type SingletonClock interface {
+	Now() int
+}
+
+type SingletonFixedClock struct {
+	now int
+}
+
+func (clock *SingletonFixedClock) Now() int {
+	return clock.now
+}
+
+type SingletonServer struct {
+	pool  *SingletonPool
+	clock SingletonClock
+}
+
+type SingletonServerApp struct {
+	Server *SingletonServer
+}
+
+type SingletonWorkerApp struct {
+	Pool *SingletonPool
+}
+
+func TestSingletons(t *testing.T) {
+	container := NewContainer()
+
+	pools := 0
+
+	container.Register(
+		func() *SingletonPool {
+			pools++
+			return &SingletonPool{id: pools}
+		},
+		func() SingletonClock { return &SingletonFixedClock{now: 1} },
+		func(pool *SingletonPool, clock SingletonClock) *SingletonServer {
+			return &SingletonServer{pool: pool, clock: clock}
+		},
+	)
+
+	var server SingletonServerApp
+	container.Resolve(&server)
+
+	var worker SingletonWorkerApp
+	container.Resolve(&worker)
+
+	if pools != 1 || worker.Pool != server.Server.pool {
+		t.Errorf("Pool was constructed %d times for separate resolutions", pools)
+	}
+
+	var again SingletonServerApp
+	container.Resolve(&again)
+
+	if again.Server != server.Server {
+		t.Errorf("Server was not reused by a later resolution")
+	}
+
+	var overridden SingletonServerApp
+
+	container.ResolveWith(&overridden, map[reflect.Type]interface{}{
+		reflect.TypeOf((*SingletonClock)(nil)).Elem(): &SingletonFixedClock{now: 2},
+	})
+
+	if overridden.Server == server.Server || overridden.Server.clock.Now() != 2 || pools != 2 {
+		t.Errorf("Resolution with overrides reused the singletons of the container")
+	}
+
+	disposer, err := container.ResolveWithDisposer(&SingletonWorkerApp{})
+
+	if err != nil || pools != 3 {
+		t.Fatalf("Resolution with a disposer reused the singletons of the container")
+	}
+
+	disposer.Dispose()
+
+	var last SingletonWorkerApp
+	container.Resolve(&last)
+
+	if last.Pool != server.Server.pool || pools != 3 {
+		t.Errorf("Singletons were replaced by isolated resolutions")
+	}
+
+	container.Override(func() *SingletonPool { return &SingletonPool{id: -1} })
+
+	var replaced SingletonServerApp
+	container.Resolve(&replaced)
+
+	if replaced.Server.pool.id != -1 {
+		t.Errorf("Singletons were reused after an override")
+	}
+}
+
+type SingletonShard struct {
+	id   int
+	pool *SingletonPool
+}
+
+func TestSingletonsShared(t *testing.T) {
+	container := NewContainer()
+
+	pools := 0
+
+	container.Register(func() *SingletonPool {
+		pools++
+		return &SingletonPool{id: pools}
+	})
+	container.Register(func(pool *SingletonPool) *SingletonServer {
+		return &SingletonServer{pool: pool}
+	})
+	container.RegisterKeyed(func(id int, pool *SingletonPool) *SingletonShard {
+		return &SingletonShard{id: id, pool: pool}
+	})
+
+	var worker SingletonWorkerApp
+	var server SingletonServerApp
+	container.ResolveAll(&worker, &server)
+
+	var child SingletonWorkerApp
+	container.NewChild().Resolve(&child)
+
+	if child.Pool != worker.Pool {
+		t.Errorf("Child did not share the pool of its parent")
+	}
+
+	if err := container.Invoke(func(pool *SingletonPool) {
+		if pool != worker.Pool {
+			t.Errorf("Invoke did not share the pool")
+		}
+	}); err != nil {
+		t.Fatalf("Function could not be invoked: %s", err)
+	}
+
+	pool, err := InvokeResult[*SingletonPool](container, func(pool *SingletonPool) *SingletonPool { return pool })
+
+	if err != nil || pool != worker.Pool {
+		t.Errorf("InvokeResult did not share the pool: %v", err)
+	}
+
+	shard, err := GetKeyed[*SingletonShard](container, 1)
+
+	if err != nil || shard.pool != worker.Pool {
+		t.Errorf("Keyed constructor did not share the pool: %v", err)
+	}
+
+	if pools != 1 {
+		t.Errorf("Pool was constructed %d times", pools)
+	}
+
+	shadowing := container.NewChild()
+	shadowing.Register(func() *SingletonPool { return &SingletonPool{id: -1} })
+
+	var shadowed SingletonServerApp
+	shadowing.Resolve(&shadowed)
+
+	if shadowed.Server == server.Server || shadowed.Server.pool.id != -1 {
+		t.Errorf("Child shadowing the pool received the server of its parent")
+	}
+}
+
+func TestSingletonsKeyedFirst(t *testing.T) {
+	container := NewContainer()
+
+	pools := 0
+
+	container.Register(func() *SingletonPool {
+		pools++
+		return &SingletonPool{id: pools}
+	})
+	container.RegisterKeyed(func(id int, pool *SingletonPool) *SingletonShard {
+		return &SingletonShard{id: id, pool: pool}
+	})
+
+	shard, err := GetKeyed[*SingletonShard](container, 1)
+
+	if err != nil {
+		t.Fatalf("Shard could not be constructed: %s", err)
+	}
+
+	var worker SingletonWorkerApp
+	container.Resolve(&worker)
+
+	if worker.Pool != shard.pool || pools != 1 {
+		t.Errorf("Resolution did not share the pool of the keyed constructor")
+	}
+}
+
+type SingletonHandler interface {
+	Handle() string
+}
+
+type SingletonStartHandler struct{}
+
+func (*SingletonStartHandler) Handle() string {
+	return "start"
+}
+
+type SingletonStopHandler struct{}
+
+func (*SingletonStopHandler) Handle() string {
+	return "stop"
+}
+
+type SingletonRouter struct {
+	handlers []SingletonHandler
+}
+
+type SingletonRouterApp struct {
+	Router *SingletonRouter
+}
+
+func TestSingletonsLateRegistration(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		func(handlers []SingletonHandler) *SingletonRouter { return &SingletonRouter{handlers: handlers} },
+		func() *SingletonStartHandler { return &SingletonStartHandler{} },
+	)
+
+	child := container.NewChild()
+
+	// The child constructs the router from the constructors of its parent
+	var cached, first SingletonRouterApp
+	child.Resolve(&cached)
+	container.Resolve(&first)
+
+	container.Register(func() *SingletonStopHandler { return &SingletonStopHandler{} })
+
+	var second, inherited SingletonRouterApp
+	container.Resolve(&second)
+	child.Resolve(&inherited)
+
+	if len(second.Router.handlers) != 2 {
+		t.Errorf("Router cached before a registration received %d handlers", len(second.Router.handlers))
+	}
+
+	if len(inherited.Router.handlers) != 2 {
+		t.Errorf("Router cached by a child before a registration of its parent received %d handlers", len(inherited.Router.handlers))
+	}
+}
+
+type SingletonPoolRouterApp struct {
+	Pool   *SingletonPool
+	Router *SingletonRouter
+}
+
+func TestSingletonsUnrelatedRegistration(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		func() *SingletonPool { return &SingletonPool{id: 1} },
+		func(handlers []SingletonHandler) *SingletonRouter { return &SingletonRouter{handlers: handlers} },
+		func() *SingletonStartHandler { return &SingletonStartHandler{} },
+	)
+
+	child := container.NewChild()
+
+	var first, inherited SingletonPoolRouterApp
+	container.Resolve(&first)
+	child.Resolve(&inherited)
+
+	container.Register(func() *SingletonStopHandler { return &SingletonStopHandler{} })
+
+	var second, again SingletonPoolRouterApp
+	container.Resolve(&second)
+	child.Resolve(&again)
+
+	if second.Pool != first.Pool || again.Pool != first.Pool {
+		t.Errorf("Pool was dropped by the registration of an unrelated handler")
+	}
+
+	if second.Router == first.Router || len(second.Router.handlers) != 2 || len(again.Router.handlers) != 2 {
+		t.Errorf("Router depending on the registered handler was reused")
+	}
+
+	container.Override(func() *SingletonStartHandler { return &SingletonStartHandler{} })
+
+	var replaced SingletonPoolRouterApp
+	container.Resolve(&replaced)
+
+	if replaced.Pool != first.Pool || replaced.Router == second.Router {
+		t.Errorf("Override did not drop exactly the singletons depending on the replaced handler")
+	}
+}
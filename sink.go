@@ -0,0 +1,47 @@
+package injector
+
+import (
+	"fmt"
+)
+
+// ErrorSink collects non-fatal errors of constructors that still produce a
+// usable value, e.g. a registry that loaded only some of its plugins. The
+// errors are reported as warnings of the resolution instead of failing it.
+//
+//   func NewPlugins(sink *injector.ErrorSink) *Plugins {
+//     …
+//     sink.Report(err)
+//   }
+//
+//   container.Resolve(&app)
+//   warnings := container.Warnings()
+type ErrorSink struct {
+	resolver *resolver
+	source   string
+}
+
+func (sink *ErrorSink) synthesize(_resolver *resolver) error {
+	sink.resolver = _resolver
+	sink.source = "root"
+
+	if pending := _resolver.PendingConstructors; len(pending) > 0 {
+		sink.source = fmt.Sprintf("constructor '%s'", pending[len(pending)-1].Function.Type())
+	}
+
+	return nil
+}
+
+// Report adds a warning to the resolution. Nil errors are ignored.
+func (sink *ErrorSink) Report(err error) {
+	if err == nil {
+		return
+	}
+
+	sink.resolver.Warnings = append(sink.resolver.Warnings, fmt.Errorf("Warning of %s: %w", sink.source, err))
+}
+
+// Warnings returns the errors reported to an ErrorSink during the last
+// resolution in the order they were reported.
+func (container *Container) Warnings() []error {
+	return append([]error(nil), container.resolvedWarnings...)
+}
@@ -0,0 +1,50 @@
+package injector
+
+import (
+	"errors"
+	"testing"
+)
+
+type SinkApp struct {
+	Plugins *SinkPlugins
+}
+
+type SinkPlugins struct {
+	loaded []string
+}
+
+var errSinkPlugin = errors.New("plugin 'broken' could not be loaded")
+
+func NewSinkPlugins(sink *ErrorSink) *SinkPlugins {
+	sink.Report(errSinkPlugin)
+	sink.Report(nil)
+
+	return &SinkPlugins{
+		loaded: []string{"auth"},
+	}
+}
+
+func TestErrorSink(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewSinkPlugins)
+
+	app := &SinkApp{}
+	container.Resolve(app)
+
+	if len(app.Plugins.loaded) != 1 {
+		t.Errorf("Plugins could not be resolved")
+	}
+
+	warnings := container.Warnings()
+
+	if len(warnings) != 1 || !errors.Is(warnings[0], errSinkPlugin) {
+		t.Errorf("Warning of plugins was not reported: %v", warnings)
+	}
+
+	container.Resolve(&struct{}{})
+
+	if len(container.Warnings()) != 0 {
+		t.Errorf("Warnings of previous resolution were kept")
+	}
+}
@@ -0,0 +1,263 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DeduplicateBy configures slices of type []T to contain only one value per
+// key. The first value of each key is kept, dropping later values with the
+// same key.
+//
+//   injector.NewContainer(injector.DeduplicateBy(func(plugin Plugin) string {
+//     return plugin.ID()
+//   }))
+//
+// Without a key function values are only deduplicated if they point to the
+// same instance.
+func DeduplicateBy[T any, K comparable](key func(T) K) Option {
+	return func(container *Container) {
+		_type := reflect.TypeOf((*T)(nil)).Elem()
+
+		container.keyByType[_type] = func(value reflect.Value) interface{} {
+			return key(value.Interface().(T))
+		}
+	}
+}
+
+// FilterBy configures slices of type []T to contain only the values of
+// constructors whose registration satisfies the predicate. The predicate is
+// evaluated when the members of a slice are collected, so the constructors of
+// excluded members are not invoked for the slice.
+//
+//   injector.NewContainer(injector.FilterBy[Plugin](func(registration injector.Registration) bool {
+//     return registration.Tags["enabled"] == "true"
+//   }))
+func FilterBy[T any](predicate func(Registration) bool) Option {
+	return func(container *Container) {
+		container.filterByType[reflect.TypeOf((*T)(nil)).Elem()] = predicate
+	}
+}
+
+// filterMembers returns the constructors that may be members of slices of
+// the given element type.
+func (container *Container) filterMembers(_type reflect.Type, constructors []*constructor) []*constructor {
+	predicate, ok := container.filterByType[_type]
+
+	if !ok {
+		return constructors
+	}
+
+	var members []*constructor
+
+	for _, constructor := range constructors {
+		if predicate(constructor.registration()) {
+			members = append(members, constructor)
+		}
+	}
+
+	return members
+}
+
+// resolveFiltered resolves a slice type whose members are filtered or ordered
+// by depth.
+func (_resolver *resolver) resolveFiltered(sliceType reflect.Type) (reflect.Value, error) {
+	_type := sliceType.Elem()
+	return _resolver.provideSlice(sliceType, _resolver.Container.filterMembers(_type, _resolver.Container.findConstructors(_type)))
+}
+
+// provideSlice creates a slice of the given type from the values of the
+// constructors, sorted by the comparator of its element type.
+func (_resolver *resolver) provideSlice(sliceType reflect.Type, constructors []*constructor) (reflect.Value, error) {
+	values, err := _resolver.provideValues(sliceType.Elem(), constructors)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if _resolver.Container.depthOrdered[sliceType.Elem()] {
+		_resolver.sortByDepth(constructors, values)
+	}
+
+	return _resolver.sortSlice(_resolver.Container.sliceOf(sliceType, values))
+}
+
+// provideValues returns the values of the constructors in their order.
+func (_resolver *resolver) provideValues(_type reflect.Type, constructors []*constructor) ([]reflect.Value, error) {
+	var values []reflect.Value
+
+	for _, constructor := range constructors {
+		value, err := _resolver.provide(constructor, _type)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// comparatorType returns the type of the comparators sorting slices of the
+// given element type, e.g. func(a, b Handler) int.
+func comparatorType(_type reflect.Type) reflect.Type {
+	return reflect.FuncOf([]reflect.Type{_type, _type}, []reflect.Type{reflect.TypeOf(0)}, false)
+}
+
+// sorted reports whether slices of the given element type are sorted by a
+// comparator or by depth instead of being in registration order.
+func (container *Container) sorted(_type reflect.Type) bool {
+	return container.depthOrdered[_type] || len(container.findConstructors(comparatorType(_type))) > 0
+}
+
+// sortSlice sorts the slice stably with the comparator registered for its
+// element type. Slices without a comparator are returned as they are.
+//
+//   container.RegisterInstance(func(a, b Handler) int {
+//     return a.Weight() - b.Weight()
+//   })
+func (_resolver *resolver) sortSlice(sliceValue reflect.Value) (reflect.Value, error) {
+	_type := comparatorType(sliceValue.Type().Elem())
+
+	if len(_resolver.Container.findConstructors(_type)) == 0 {
+		return sliceValue, nil
+	}
+
+	comparator, err := _resolver.resolveType(_type)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// Comparators are not constructed during a dry run
+	if !comparator.IsValid() || comparator.IsNil() {
+		return sliceValue, nil
+	}
+
+	sort.SliceStable(sliceValue.Interface(), func(i, j int) bool {
+		return comparator.Call([]reflect.Value{sliceValue.Index(i), sliceValue.Index(j)})[0].Int() < 0
+	})
+
+	return sliceValue, nil
+}
+
+// findOrderedConstructors returns the members of a slice dependency with the
+// named constructors moved to the front in the given order. Unlisted members
+// keep their order behind them.
+func (container *Container) findOrderedConstructors(dependency *dependency) ([]*constructor, error) {
+	unordered := *dependency
+	unordered.Order = nil
+
+	constructors, err := container.findDependencyConstructors(&unordered)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ordered []*constructor
+
+	listed := make(map[*constructor]bool)
+
+	for _, name := range dependency.Order {
+		var found *constructor
+
+		for _, constructor := range constructors {
+			if constructor.Name == name {
+				found = constructor
+			}
+		}
+
+		if found == nil {
+			return nil, fmt.Errorf("Unknown name '%s' in order of type '%s'", name, dependency.Type)
+		}
+
+		if !listed[found] {
+			listed[found] = true
+			ordered = append(ordered, found)
+		}
+	}
+
+	for _, constructor := range constructors {
+		if !listed[constructor] {
+			ordered = append(ordered, constructor)
+		}
+	}
+
+	return ordered, nil
+}
+
+// resolveOrdered resolves a slice dependency with an order.
+func (_resolver *resolver) resolveOrdered(dependency *dependency) (reflect.Value, error) {
+	constructors, err := _resolver.Container.findOrderedConstructors(dependency)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	values, err := _resolver.provideValues(dependency.Type.Elem(), constructors)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// An explicit order takes precedence over the comparator
+	return _resolver.Container.sliceOf(dependency.Type, values), nil
+}
+
+// sliceOf creates a slice of the given type containing the deduplicated
+// values.
+func (container *Container) sliceOf(sliceType reflect.Type, values []reflect.Value) reflect.Value {
+	key, ok := container.keyByType[sliceType.Elem()]
+
+	if !ok {
+		key = identityKey
+	}
+
+	sliceValue := reflect.MakeSlice(sliceType, 0, len(values))
+	seen := make(map[interface{}]bool)
+
+	for _, value := range values {
+		if key := key(value); key != nil {
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+		}
+
+		sliceValue = reflect.Append(sliceValue, value)
+	}
+
+	return sliceValue
+}
+
+type instanceKey struct {
+	Type    reflect.Type
+	Pointer uintptr
+}
+
+// identityKey identifies values pointing to an instance, other values are
+// never considered duplicates.
+func identityKey(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+	default:
+		return nil
+	}
+
+	// Pointers to zero-sized values may legitimately share an address
+	if value.IsNil() || value.Kind() == reflect.Ptr && value.Type().Elem().Size() == 0 {
+		return nil
+	}
+
+	return instanceKey{
+		Type:    value.Type(),
+		Pointer: value.Pointer(),
+	}
+}
@@ -0,0 +1,447 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type DeduplicateApp struct {
+	Plugins []DeduplicatePlugin
+}
+
+type DeduplicatePlugin interface {
+	ID() string
+}
+
+type DeduplicateFirstPlugin struct {
+	id string
+}
+
+func (plugin *DeduplicateFirstPlugin) ID() string {
+	return plugin.id
+}
+
+type DeduplicateSecondPlugin struct {
+	id string
+}
+
+func (plugin *DeduplicateSecondPlugin) ID() string {
+	return plugin.id
+}
+
+func TestDeduplicateBy(t *testing.T) {
+	container := NewContainer(DeduplicateBy(func(plugin DeduplicatePlugin) string {
+		return plugin.ID()
+	}))
+
+	container.Register(
+		func() *DeduplicateFirstPlugin { return &DeduplicateFirstPlugin{id: "auth"} },
+		func() *DeduplicateSecondPlugin { return &DeduplicateSecondPlugin{id: "auth"} },
+		func() DeduplicatePlugin { return &DeduplicateSecondPlugin{id: "cache"} },
+	)
+
+	app := &DeduplicateApp{}
+	container.Resolve(app)
+
+	plugins := app.Plugins
+
+	if len(plugins) != 2 || plugins[0].ID() != "auth" || plugins[1].ID() != "cache" {
+		t.Errorf("Plugins could not be deduplicated")
+	}
+
+	if _, ok := plugins[0].(*DeduplicateFirstPlugin); !ok {
+		t.Errorf("First plugin per key was not kept")
+	}
+}
+
+func TestDeduplicateInstance(t *testing.T) {
+	container := NewContainer()
+
+	plugin := &DeduplicateFirstPlugin{id: "auth"}
+
+	container.Register(
+		func() *DeduplicateFirstPlugin { return plugin },
+		func() DeduplicatePlugin { return plugin },
+		func() *DeduplicateSecondPlugin { return &DeduplicateSecondPlugin{id: "auth"} },
+	)
+
+	app := &DeduplicateApp{}
+	container.Resolve(app)
+
+	if len(app.Plugins) != 2 {
+		t.Errorf("Plugins could not be deduplicated")
+	}
+}
+
+type MixedApp struct {
+	Foo *MixedFoo
+}
+
+type MixedFoo struct {
+	bars []MixedBar
+}
+
+type MixedBar interface {
+	Bar() string
+}
+
+type MixedPointerBar struct {
+	value string
+}
+
+func (bar *MixedPointerBar) Bar() string {
+	return bar.value
+}
+
+type MixedValueBar struct {
+	value string
+}
+
+func (bar MixedValueBar) Bar() string {
+	return bar.value
+}
+
+func NewMixedFoo(bars []MixedBar) *MixedFoo {
+	return &MixedFoo{
+		bars: bars,
+	}
+}
+
+func TestMixed(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		NewMixedFoo,
+		func() *MixedPointerBar { return &MixedPointerBar{value: "pointer_bar"} },
+		func() MixedValueBar { return MixedValueBar{value: "value_bar"} },
+		func() MixedBar { return MixedValueBar{value: "interface_bar"} },
+	)
+
+	app := &MixedApp{}
+	container.Resolve(app)
+
+	bars := app.Foo.bars
+
+	if len(bars) != 3 || bars[0].Bar() != "pointer_bar" || bars[1].Bar() != "value_bar" || bars[2].Bar() != "interface_bar" {
+		t.Errorf("Foo could not be resolved")
+	}
+
+	if _, ok := bars[1].(MixedValueBar); !ok {
+		t.Errorf("Value bar was not stored as value")
+	}
+}
+
+type MixedInstanceApp struct {
+	Bars []MixedBar
+}
+
+func TestMixedInstance(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *MixedPointerBar { return &MixedPointerBar{value: "constructor_bar"} })
+	container.RegisterInstance(MixedValueBar{value: "instance_bar"})
+	container.Register(func() MixedBar { return MixedValueBar{value: "interface_bar"} })
+
+	app := &MixedInstanceApp{}
+	container.Resolve(app)
+
+	bars := app.Bars
+
+	if len(bars) != 3 || bars[0].Bar() != "constructor_bar" || bars[1].Bar() != "instance_bar" || bars[2].Bar() != "interface_bar" {
+		t.Errorf("Instances and constructors could not be resolved in registration order")
+	}
+}
+
+type SingleSliceApp struct {
+	Plugins []DeduplicatePlugin
+	Plugin  *DeduplicateFirstPlugin
+}
+
+func TestSingleImplementationSlice(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func() *DeduplicateFirstPlugin { return &DeduplicateFirstPlugin{id: "auth"} })
+
+	app := &SingleSliceApp{}
+	container.Resolve(app)
+
+	if len(app.Plugins) != 1 || app.Plugins[0] != app.Plugin {
+		t.Errorf("Single implementation could not be injected as slice")
+	}
+}
+
+func TestManyImplementationsSlice(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(
+		func() *DeduplicateFirstPlugin { return &DeduplicateFirstPlugin{id: "auth"} },
+		func() *DeduplicateSecondPlugin { return &DeduplicateSecondPlugin{id: "cache"} },
+	)
+
+	app := &DeduplicateApp{}
+
+	if err := container.ResolveContext(context.Background(), app); err != nil {
+		t.Fatalf("Slice could not be resolved: %s", err)
+	}
+
+	if len(app.Plugins) != 2 {
+		t.Errorf("Unexpected number of plugins %d", len(app.Plugins))
+	}
+}
+
+type FilterApp struct {
+	Plugins []DeduplicatePlugin
+	Group   []DeduplicatePlugin `inject:"group:plugins"`
+}
+
+func TestFilterBy(t *testing.T) {
+	container := NewContainer(FilterBy[DeduplicatePlugin](func(registration Registration) bool {
+		return registration.Tags["enabled"] == "true"
+	}))
+
+	constructed := 0
+
+	container.RegisterTagged(func() *DeduplicateFirstPlugin {
+		return &DeduplicateFirstPlugin{id: "auth"}
+	}, "enabled=true")
+	container.RegisterTagged(func() *DeduplicateSecondPlugin {
+		constructed++
+		return &DeduplicateSecondPlugin{id: "cache"}
+	}, "enabled=false")
+	container.RegisterGroup("plugins", func() DeduplicatePlugin {
+		constructed++
+		return &DeduplicateSecondPlugin{id: "metrics"}
+	})
+
+	app := &FilterApp{}
+	container.Resolve(app)
+
+	if len(app.Plugins) != 1 || app.Plugins[0].ID() != "auth" {
+		t.Errorf("Plugins could not be filtered")
+	}
+
+	if len(app.Group) != 0 {
+		t.Errorf("Group could not be filtered")
+	}
+
+	if constructed != 0 {
+		t.Errorf("Excluded plugins were constructed")
+	}
+}
+
+type SliceOrderApp struct {
+	Middlewares []SliceOrderMiddleware `inject:"order=auth,logging"`
+}
+
+type SliceOrderUnknownApp struct {
+	Middlewares []SliceOrderMiddleware `inject:"order=metrics"`
+}
+
+type SliceOrderMiddleware interface {
+	Name() string
+}
+
+type SliceOrderNamedMiddleware struct {
+	name string
+}
+
+func (middleware *SliceOrderNamedMiddleware) Name() string {
+	return middleware.name
+}
+
+func newSliceOrderMiddleware(name string) func() SliceOrderMiddleware {
+	return func() SliceOrderMiddleware {
+		return &SliceOrderNamedMiddleware{name: name}
+	}
+}
+
+func TestSliceOrder(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterNamed("router", newSliceOrderMiddleware("router"))
+	container.RegisterNamed("logging", newSliceOrderMiddleware("logging"))
+	container.Register(newSliceOrderMiddleware("recovery"))
+	container.RegisterNamed("auth", newSliceOrderMiddleware("auth"))
+
+	app := &SliceOrderApp{}
+	container.Resolve(app)
+
+	var names []string
+
+	for _, middleware := range app.Middlewares {
+		names = append(names, middleware.Name())
+	}
+
+	if strings.Join(names, ",") != "auth,logging,router,recovery" {
+		t.Errorf("Unexpected middleware order %v", names)
+	}
+
+	if err := container.ResolveContext(context.Background(), &SliceOrderUnknownApp{}); err == nil {
+		t.Errorf("Unknown name in order was not rejected")
+	}
+}
+
+type ComparatorApp struct {
+	Handlers []ComparatorHandler
+}
+
+type ComparatorHandler interface {
+	Weight() int
+}
+
+type ComparatorWeightedHandler struct {
+	weight int
+}
+
+func (handler *ComparatorWeightedHandler) Weight() int {
+	return handler.weight
+}
+
+func newComparatorHandler(weight int) func() ComparatorHandler {
+	return func() ComparatorHandler {
+		return &ComparatorWeightedHandler{weight: weight}
+	}
+}
+
+func TestSliceComparator(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(newComparatorHandler(3), newComparatorHandler(1), newComparatorHandler(2))
+	container.RegisterInstance(func(a, b ComparatorHandler) int {
+		return a.Weight() - b.Weight()
+	})
+
+	app := &ComparatorApp{}
+	container.Resolve(app)
+
+	if len(app.Handlers) != 3 {
+		t.Fatalf("Handlers could not be resolved")
+	}
+
+	for i, handler := range app.Handlers {
+		if handler.Weight() != i+1 {
+			t.Errorf("Handlers were not sorted by the comparator")
+		}
+	}
+}
+
+type ChainApp struct {
+	Handlers []ChainHandler
+	Logger   *ChainLogger
+}
+
+type ChainHandler interface {
+	Logger() *ChainLogger
+}
+
+type ChainLogger struct {
+	output *ChainOutput
+}
+
+type ChainOutput struct {
+	name string
+}
+
+type ChainUsersHandler struct {
+	logger *ChainLogger
+}
+
+func (handler *ChainUsersHandler) Logger() *ChainLogger {
+	return handler.logger
+}
+
+type ChainStatusHandler struct {
+	logger *ChainLogger
+}
+
+func (handler *ChainStatusHandler) Logger() *ChainLogger {
+	return handler.logger
+}
+
+func TestSliceDependencyChain(t *testing.T) {
+	container := NewContainer()
+
+	loggers := 0
+
+	container.Register(
+		func(logger *ChainLogger) *ChainUsersHandler { return &ChainUsersHandler{logger: logger} },
+		func(logger *ChainLogger) *ChainStatusHandler { return &ChainStatusHandler{logger: logger} },
+		func(output *ChainOutput) *ChainLogger {
+			loggers++
+			return &ChainLogger{output: output}
+		},
+		func() *ChainOutput { return &ChainOutput{name: "stdout"} },
+	)
+
+	app := &ChainApp{}
+	container.Resolve(app)
+
+	if len(app.Handlers) != 2 || loggers != 1 {
+		t.Fatalf("Handlers could not be resolved with a single logger")
+	}
+
+	for _, handler := range app.Handlers {
+		if handler.Logger() != app.Logger || handler.Logger().output.name != "stdout" {
+			t.Errorf("Handler was not built with the shared logger")
+		}
+	}
+}
+
+type EmbeddingNamed interface {
+	Name() string
+}
+
+type EmbeddingReader interface {
+	EmbeddingNamed
+	Read() string
+}
+
+type EmbeddingWriter interface {
+	EmbeddingNamed
+	Write() string
+}
+
+type EmbeddingFile struct {
+	name string
+}
+
+func (file *EmbeddingFile) Name() string {
+	return file.name
+}
+
+func (file *EmbeddingFile) Read() string {
+	return "read"
+}
+
+func (file *EmbeddingFile) Write() string {
+	return "write"
+}
+
+type EmbeddingOther struct {
+	name string
+}
+
+func (other *EmbeddingOther) Name() string {
+	return other.name
+}
+
+func TestSliceEmbeddedInterfaces(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		container := NewContainer()
+
+		container.Register(
+			func() *EmbeddingFile { return &EmbeddingFile{name: "file"} },
+			func(file *EmbeddingFile) EmbeddingReader { return file },
+			func() *EmbeddingOther { return &EmbeddingOther{name: "other"} },
+			func(file *EmbeddingFile) EmbeddingWriter { return file },
+		)
+
+		app := &struct{ Named []EmbeddingNamed }{}
+		container.Resolve(app)
+
+		if len(app.Named) != 2 || app.Named[0].Name() != "file" || app.Named[1].Name() != "other" {
+			t.Fatalf("File was not collected once at a stable position: %v", app.Named)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterStruct registers a constructor for a pointer to the struct that
+// injects all fields of the struct like the fields of a root, so no
+// constructor needs to be written for plain wiring structs.
+//
+//   type Handlers struct {
+//     Users  *UserHandler
+//     Config HandlerConfig
+//     Admin  Handler `inject:"name=admin"`
+//   }
+//
+//   container.RegisterStruct((*Handlers)(nil))
+//
+// All fields of the struct must be exported.
+func (container *Container) RegisterStruct(pointer interface{}) {
+	_type := reflect.TypeOf(pointer)
+
+	if _type == nil || _type.Kind() != reflect.Ptr || _type.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("Struct '%s' must be given as pointer to a struct", _type))
+	}
+
+	structType := _type.Elem()
+
+	var params []reflect.Type
+	var dependencies []*dependency
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if field.PkgPath != "" {
+			panic(fmt.Errorf("Field '%s' of struct '%s' is unexported and cannot be injected", field.Name, structType))
+		}
+
+		dependency, err := newFieldDependency(field)
+
+		if err != nil {
+			panic(err)
+		}
+
+		params = append(params, field.Type)
+		dependencies = append(dependencies, dependency)
+	}
+
+	function := reflect.MakeFunc(
+		reflect.FuncOf(params, []reflect.Type{_type}, false),
+		func(arguments []reflect.Value) []reflect.Value {
+			value := reflect.New(structType)
+
+			for i, argument := range arguments {
+				value.Elem().Field(i).Set(argument)
+			}
+
+			return []reflect.Value{value}
+		},
+	)
+
+	details := newConstructor(function.Interface())
+	details.Dependencies = dependencies
+
+	container.addConstructors(details)
+}
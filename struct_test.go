@@ -0,0 +1,62 @@
+package injector
+
+import (
+	"testing"
+)
+
+type StructApp struct {
+	Handlers *StructHandlers
+}
+
+type StructHandlers struct {
+	Foo  PointerFoo
+	Bar  *StructBar
+	Name string `inject:"name=title"`
+}
+
+type StructBar struct {
+	value string
+}
+
+func NewStructBar() *StructBar {
+	return &StructBar{
+		value: "bar",
+	}
+}
+
+func TestRegisterStruct(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewPointerFoo, NewPointerBar, NewStructBar)
+	container.RegisterNamed("title", func() string { return "handlers" })
+	container.RegisterStruct((*StructHandlers)(nil))
+
+	app := &StructApp{}
+	container.Resolve(app)
+
+	handlers := app.Handlers
+
+	if handlers.Foo.bar.value != "bar" {
+		t.Errorf("Value field could not be resolved")
+	}
+
+	if handlers.Bar == nil || handlers.Bar.value != "bar" {
+		t.Errorf("Pointer field could not be resolved")
+	}
+
+	if handlers.Name != "handlers" {
+		t.Errorf("Tagged field could not be resolved")
+	}
+}
+
+func TestRegisterStructUnexported(t *testing.T) {
+	container := NewContainer()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Unexported field was not rejected")
+		}
+	}()
+
+	container.RegisterStruct((*StructBar)(nil))
+}
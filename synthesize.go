@@ -0,0 +1,29 @@
+package injector
+
+import (
+	"reflect"
+)
+
+// synthesized is implemented by types the container creates itself instead of
+// looking up a constructor.
+type synthesized interface {
+	synthesize(_resolver *resolver) error
+}
+
+var synthesizedType = reflect.TypeOf((*synthesized)(nil)).Elem()
+
+// synthesize creates the value of a synthesized type. It reports false if the
+// type is not synthesized.
+func (_resolver *resolver) synthesize(_type reflect.Type) (reflect.Value, bool, error) {
+	if _type.Kind() != reflect.Ptr || !_type.Implements(synthesizedType) {
+		return reflect.Value{}, false, nil
+	}
+
+	value := reflect.New(_type.Elem())
+
+	if err := value.Interface().(synthesized).synthesize(_resolver); err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	return value, true, nil
+}
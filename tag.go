@@ -0,0 +1,287 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// dependency describes what is injected into a root field or a constructor
+// parameter.
+type dependency struct {
+	Type         reflect.Type
+	Name         string
+	Group        string
+	GroupNames   string
+	ConcreteType string
+	Tags         map[string]string
+	Order        []string
+}
+
+// tagged reports whether the dependency deviates from plain type matching.
+func (dependency *dependency) tagged() bool {
+	return dependency.Name != "" || dependency.Group != "" || dependency.GroupNames != "" || dependency.ConcreteType != "" || len(dependency.Tags) > 0 || len(dependency.Order) > 0
+}
+
+// RegisterTagged registers a constructor like Register and labels it with
+// tags, each being a key and a value separated by an equals sign. Tagged
+// dependencies only receive the constructors carrying all of their tags.
+//
+//   container.RegisterTagged(NewPostgres, "env=prod", "region=eu")
+//
+//   type App struct {
+//     Database Database `inject:"env=prod;region=eu"`
+//   }
+func (container *Container) RegisterTagged(_constructor interface{}, tags ...string) {
+	if err := container.RegisterTaggedE(_constructor, tags...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterTaggedE works like RegisterTagged but returns an error instead of
+// panicking if the constructor or a tag is invalid.
+func (container *Container) RegisterTaggedE(_constructor interface{}, tags ...string) error {
+	details, err := parseConstructor(_constructor)
+
+	if err != nil {
+		return err
+	}
+
+	details.Tags = make(map[string]string)
+
+	for _, tag := range tags {
+		key, value := splitClause(tag)
+
+		if key == "" || value == "" || reservedKeys[key] {
+			return fmt.Errorf("Invalid tag '%s' of constructor '%s'", tag, details.Function.Type())
+		}
+
+		details.Tags[key] = value
+	}
+
+	container.addConstructors(details)
+
+	return nil
+}
+
+var reservedKeys = map[string]bool{
+	"group": true,
+	"names": true,
+	"name":  true,
+	"type":  true,
+	"order": true,
+}
+
+// RegisterWithTags registers a constructor like Register and annotates its
+// parameters with inject tags as known from root fields. The n-th tag applies
+// to the n-th parameter, empty tags leave a parameter as is.
+//
+//   func NewHelp(commands []string) *Help {…}
+//
+//   container.RegisterWithTags(NewHelp, "names:commands")
+func (container *Container) RegisterWithTags(_constructor interface{}, tags ...string) {
+	if err := container.RegisterWithTagsE(_constructor, tags...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterWithTagsE works like RegisterWithTags but returns an error instead
+// of panicking if the constructor or a tag is invalid.
+func (container *Container) RegisterWithTagsE(_constructor interface{}, tags ...string) error {
+	details, err := parseConstructor(_constructor)
+
+	if err != nil {
+		return err
+	}
+
+	if len(tags) > len(details.Parameters) {
+		return fmt.Errorf("Constructor '%s' has fewer parameters than tags", details.Function.Type())
+	}
+
+	for i, tag := range tags {
+		dependency, err := parseDependency(details.Parameters[i], tag, fmt.Sprintf("parameter %d of constructor '%s'", i, details.Function.Type()))
+
+		if err != nil {
+			return err
+		}
+
+		details.Dependencies[i] = dependency
+	}
+
+	container.addConstructors(details)
+
+	return nil
+}
+
+// newFieldDependency parses the inject tag of a struct field.
+func newFieldDependency(structField reflect.StructField) (*dependency, error) {
+	return parseDependency(structField.Type, structField.Tag.Get("inject"), fmt.Sprintf("field '%s'", structField.Name))
+}
+
+// parseDependency parses an inject tag. The tag consists of clauses separated
+// by semicolons, each being a key and a value separated by a colon or an
+// equals sign.
+func parseDependency(_type reflect.Type, tag string, target string) (*dependency, error) {
+	dependency := &dependency{
+		Type: _type,
+	}
+
+	for _, clause := range strings.Split(tag, ";") {
+		clause = strings.TrimSpace(clause)
+
+		if clause == "" {
+			continue
+		}
+
+		key, value := splitClause(clause)
+
+		switch key {
+		case "group":
+			if _type.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("Group '%s' cannot be injected into %s of non-slice type '%s'", value, target, _type)
+			}
+
+			dependency.Group = value
+		case "names":
+			if !reflect.TypeOf([]string(nil)).ConvertibleTo(_type) {
+				return nil, fmt.Errorf("Names of group '%s' cannot be injected into %s of type '%s'", value, target, _type)
+			}
+
+			dependency.GroupNames = value
+		case "name":
+			dependency.Name = value
+		case "type":
+			if _type.Kind() == reflect.Slice {
+				return nil, fmt.Errorf("Type '%s' cannot be injected into %s of slice type '%s'", value, target, _type)
+			}
+
+			dependency.ConcreteType = value
+		case "order":
+			if _type.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("Order '%s' cannot be applied to %s of non-slice type '%s'", value, target, _type)
+			}
+
+			for _, name := range strings.Split(value, ",") {
+				dependency.Order = append(dependency.Order, strings.TrimSpace(name))
+			}
+		default:
+			// Any other key with a value selects constructors by their tags
+			if value == "" {
+				return nil, fmt.Errorf("Unknown key '%s' in inject tag of %s", key, target)
+			}
+
+			if dependency.Tags == nil {
+				dependency.Tags = make(map[string]string)
+			}
+
+			dependency.Tags[key] = value
+		}
+	}
+
+	if len(dependency.Tags) > 0 && (dependency.Name != "" || dependency.Group != "" || dependency.GroupNames != "" || dependency.ConcreteType != "") {
+		return nil, fmt.Errorf("Tags cannot be combined with name, group, names or type in inject tag of %s", target)
+	}
+
+	return dependency, nil
+}
+
+func splitClause(clause string) (string, string) {
+	index := strings.IndexAny(clause, ":=")
+
+	if index < 0 {
+		return clause, ""
+	}
+
+	return strings.TrimSpace(clause[:index]), strings.TrimSpace(clause[index+1:])
+}
+
+// findConcreteConstructor returns the constructor whose return type is named
+// in the dependency, as printed by the %T verb.
+func (container *Container) findConcreteConstructor(dependency *dependency) (*constructor, error) {
+	var constructors []*constructor
+
+	for _, constructor := range container.allConstructors() {
+		if !constructor.Decorator && constructor.ReturnType.String() == dependency.ConcreteType {
+			constructors = append(constructors, constructor)
+		}
+	}
+
+	if len(constructors) == 0 {
+		return nil, missingDependency(dependency.Type, "No constructor defined for type '%s'", dependency.ConcreteType)
+	}
+
+	if len(constructors) > 1 {
+		return nil, ambiguousDependency(dependency.Type, "Ambiguity detected for type '%s'", dependency.ConcreteType)
+	}
+
+	if !constructors[0].ReturnType.AssignableTo(dependency.Type) {
+		return nil, fmt.Errorf("Type '%s' is not assignable to type '%s'", dependency.ConcreteType, dependency.Type)
+	}
+
+	return constructors[0], nil
+}
+
+// findTaggedConstructors returns the constructors of the dependency type that
+// carry all tags of the dependency. A non-slice dependency must match exactly
+// one constructor.
+func (container *Container) findTaggedConstructors(dependency *dependency) ([]*constructor, error) {
+	var constructors []*constructor
+
+	for _, constructor := range container.findConstructors(innerType(dependency.Type)) {
+		if constructor.hasTags(dependency.Tags) {
+			constructors = append(constructors, constructor)
+		}
+	}
+
+	if dependency.Type.Kind() == reflect.Slice {
+		return container.filterMembers(dependency.Type.Elem(), constructors), nil
+	}
+
+	if len(constructors) == 0 {
+		return nil, missingDependency(dependency.Type, "No constructor tagged '%s' defined for type '%s'", formatTags(dependency.Tags), dependency.Type)
+	}
+
+	if len(constructors) > 1 {
+		return nil, ambiguousDependency(dependency.Type, "Ambiguity detected for tags '%s' of type '%s'", formatTags(dependency.Tags), dependency.Type)
+	}
+
+	return constructors, nil
+}
+
+func (_resolver *resolver) resolveTagged(dependency *dependency) (reflect.Value, error) {
+	constructors, err := _resolver.Container.findTaggedConstructors(dependency)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if dependency.Type.Kind() == reflect.Slice {
+		return _resolver.provideSlice(dependency.Type, constructors)
+	}
+
+	return _resolver.provide(constructors[0], dependency.Type)
+}
+
+func (constructor *constructor) hasTags(tags map[string]string) bool {
+	for key, value := range tags {
+		if constructor.Tags[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatTags renders tags sorted by key as in an inject tag.
+func formatTags(tags map[string]string) string {
+	var clauses []string
+
+	for key, value := range tags {
+		clauses = append(clauses, key+"="+value)
+	}
+
+	sort.Strings(clauses)
+
+	return strings.Join(clauses, ";")
+}
@@ -0,0 +1,176 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type ConcreteApp struct {
+	Store ConcreteStore `inject:"type=*injector.ConcreteRedisStore"`
+}
+
+type ConcreteStore interface {
+	Name() string
+}
+
+type ConcreteMemoryStore struct {
+}
+
+func (store *ConcreteMemoryStore) Name() string {
+	return "memory"
+}
+
+type ConcreteRedisStore struct {
+}
+
+func (store *ConcreteRedisStore) Name() string {
+	return "redis"
+}
+
+type ConcreteOther struct {
+}
+
+func NewConcreteMemoryStore() *ConcreteMemoryStore {
+	return &ConcreteMemoryStore{}
+}
+
+func NewConcreteRedisStore() *ConcreteRedisStore {
+	return &ConcreteRedisStore{}
+}
+
+func NewConcreteOther() *ConcreteOther {
+	return &ConcreteOther{}
+}
+
+func TestConcreteType(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewConcreteMemoryStore, NewConcreteRedisStore)
+
+	app := &ConcreteApp{}
+	container.Resolve(app)
+
+	if app.Store.Name() != "redis" {
+		t.Errorf("Store could not be resolved")
+	}
+}
+
+func TestConcreteTypeMissing(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewConcreteMemoryStore)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Missing concrete type was not detected")
+		}
+	}()
+
+	container.Resolve(&ConcreteApp{})
+}
+
+func TestConcreteTypeNotImplemented(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewConcreteMemoryStore, NewConcreteOther)
+
+	app := &struct {
+		Store ConcreteStore `inject:"type=*injector.ConcreteOther"`
+	}{}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Concrete type not implementing the interface was not detected")
+		}
+	}()
+
+	container.Resolve(app)
+}
+
+type TaggedApp struct {
+	Mailer TaggedMailer   `inject:"env=prod"`
+	Local  TaggedMailer   `inject:"env=prod;region=eu"`
+	All    []TaggedMailer `inject:"env=prod"`
+}
+
+type TaggedMailer interface {
+	Region() string
+}
+
+type TaggedSMTPMailer struct {
+	region string
+}
+
+func (mailer *TaggedSMTPMailer) Region() string {
+	return mailer.region
+}
+
+type TaggedFakeMailer struct {
+	region string
+}
+
+func (mailer *TaggedFakeMailer) Region() string {
+	return mailer.region
+}
+
+type TaggedSingleApp struct {
+	Mailer TaggedMailer `inject:"env=test"`
+}
+
+func TestTagged(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterTagged(func() *TaggedFakeMailer { return &TaggedFakeMailer{region: "us"} }, "env=test")
+	container.RegisterTagged(func() *TaggedSMTPMailer { return &TaggedSMTPMailer{region: "eu"} }, "env=prod", "region=eu")
+
+	app := &TaggedSingleApp{}
+	container.Resolve(app)
+
+	if _, ok := app.Mailer.(*TaggedFakeMailer); !ok {
+		t.Errorf("Mailer could not be selected by tag")
+	}
+}
+
+func TestTaggedAll(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterTagged(func() *TaggedFakeMailer { return &TaggedFakeMailer{region: "us"} }, "env=prod", "region=us")
+	container.RegisterTagged(func() *TaggedSMTPMailer { return &TaggedSMTPMailer{region: "eu"} }, "env=prod", "region=eu")
+
+	app := &TaggedApp{}
+
+	err := container.ResolveContext(context.Background(), app)
+
+	if err == nil || !strings.Contains(err.Error(), "Ambiguity") {
+		t.Errorf("Ambiguous tag was not detected: %v", err)
+	}
+
+	container = NewContainer()
+
+	container.RegisterTagged(func() *TaggedFakeMailer { return &TaggedFakeMailer{region: "us"} }, "env=test", "region=eu")
+	container.RegisterTagged(func() *TaggedSMTPMailer { return &TaggedSMTPMailer{region: "eu"} }, "env=prod", "region=eu")
+
+	app = &TaggedApp{}
+	container.Resolve(app)
+
+	if _, ok := app.Local.(*TaggedSMTPMailer); !ok {
+		t.Errorf("Mailer could not be selected by all tags")
+	}
+
+	if len(app.All) != 1 || app.All[0] != app.Mailer {
+		t.Errorf("Tagged slice could not be resolved")
+	}
+}
+
+func TestTaggedInvalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Invalid tag was not rejected")
+		}
+	}()
+
+	container := NewContainer()
+
+	container.RegisterTagged(NewConcreteOther, "name=other")
+}
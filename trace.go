@@ -0,0 +1,117 @@
+package injector
+
+import (
+	"context"
+	"reflect"
+)
+
+// ResolveTrace describes the constructors invoked by a resolution and how
+// they depend on each other.
+type ResolveTrace struct {
+	// Nodes contains all invoked constructors in construction order
+	Nodes []*TraceNode
+
+	// Roots contains the constructors injected into the root fields
+	Roots []*TraceNode
+}
+
+// TraceNode describes a single invoked constructor.
+type TraceNode struct {
+	Type         reflect.Type
+	Constructor  reflect.Type
+	Order        int
+	Dependencies []*TraceNode
+}
+
+type tracer struct {
+	Trace   *ResolveTrace
+	Nodes   map[*constructor]*TraceNode
+	Pending []*TraceNode
+}
+
+// Trace works like Resolve but returns an error instead of panicking along
+// with a trace of the resolution.
+//
+//   trace, err := container.Trace(&app)
+//
+//   for _, node := range trace.Nodes {
+//     fmt.Println(node.Order, node.Type, len(node.Dependencies))
+//   }
+func (container *Container) Trace(root interface{}) (*ResolveTrace, error) {
+	_tracer := newTracer()
+
+	prepare := func(_resolver *resolver) error {
+		_resolver.Tracer = _tracer
+		return nil
+	}
+
+	if err := container.resolveOwned(context.Background(), []interface{}{root}, prepare); err != nil {
+		return nil, err
+	}
+
+	return _tracer.Trace, nil
+}
+
+// ValidateTrace works like Validate and returns the trace of the resolution
+// that would happen. No constructor is invoked.
+func (container *Container) ValidateTrace(root interface{}) (*ResolveTrace, error) {
+	if err := checkRoots([]interface{}{root}); err != nil {
+		return nil, err
+	}
+
+	resolver := newResolver(context.Background(), container)
+	resolver.DryRun = true
+	resolver.Tracer = newTracer()
+
+	if _, err := resolver.resolveFields(root); err != nil {
+		return nil, err
+	}
+
+	return resolver.Tracer.Trace, nil
+}
+
+func newTracer() *tracer {
+	return &tracer{
+		Trace: &ResolveTrace{},
+		Nodes: make(map[*constructor]*TraceNode),
+	}
+}
+
+// enter links the node of the constructor to the constructor currently being
+// invoked, or to the root if there is none, and returns the node.
+func (_tracer *tracer) enter(constructor *constructor) *TraceNode {
+	node, ok := _tracer.Nodes[constructor]
+
+	if !ok {
+		node = &TraceNode{
+			Type:        constructor.ReturnType,
+			Constructor: constructor.Function.Type(),
+		}
+
+		_tracer.Nodes[constructor] = node
+	}
+
+	if len(_tracer.Pending) == 0 {
+		_tracer.Trace.Roots = appendNode(_tracer.Trace.Roots, node)
+	} else {
+		dependent := _tracer.Pending[len(_tracer.Pending)-1]
+		dependent.Dependencies = appendNode(dependent.Dependencies, node)
+	}
+
+	return node
+}
+
+func (_tracer *tracer) invoked(node *TraceNode) {
+	node.Order = len(_tracer.Trace.Nodes)
+	_tracer.Trace.Nodes = append(_tracer.Trace.Nodes, node)
+}
+
+func appendNode(nodes []*TraceNode, node *TraceNode) []*TraceNode {
+	for _, existing := range nodes {
+		if existing == node {
+			return nodes
+		}
+	}
+
+	return append(nodes, node)
+}
@@ -0,0 +1,119 @@
+package injector
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TraceApp struct {
+	Server *TraceServer
+}
+
+type TraceServer struct {
+	database *TraceDatabase
+	cache    *TraceCache
+}
+
+type TraceDatabase struct {
+	dsn string
+}
+
+type TraceCache struct {
+	database *TraceDatabase
+}
+
+func NewTraceServer(database *TraceDatabase, cache *TraceCache) *TraceServer {
+	return &TraceServer{
+		database: database,
+		cache:    cache,
+	}
+}
+
+func NewTraceDatabase() *TraceDatabase {
+	return &TraceDatabase{}
+}
+
+func NewTraceCache(database *TraceDatabase) *TraceCache {
+	return &TraceCache{
+		database: database,
+	}
+}
+
+func TestTrace(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewTraceServer, NewTraceDatabase, NewTraceCache)
+
+	app := &TraceApp{}
+
+	trace, err := container.Trace(app)
+
+	if err != nil {
+		t.Fatalf("App could not be traced: %s", err)
+	}
+
+	if app.Server == nil {
+		t.Errorf("App was not resolved")
+	}
+
+	assertTrace(t, trace)
+}
+
+func TestValidateTrace(t *testing.T) {
+	container := NewContainer()
+
+	constructed := false
+
+	container.Register(NewTraceServer, func() *TraceDatabase {
+		constructed = true
+		return &TraceDatabase{}
+	}, NewTraceCache)
+
+	trace, err := container.ValidateTrace(&TraceApp{})
+
+	if err != nil {
+		t.Fatalf("App could not be traced: %s", err)
+	}
+
+	if constructed {
+		t.Errorf("Constructor was invoked")
+	}
+
+	assertTrace(t, trace)
+}
+
+func assertTrace(t *testing.T, trace *ResolveTrace) {
+	var types []reflect.Type
+
+	for _, node := range trace.Nodes {
+		types = append(types, node.Type)
+	}
+
+	expected := []reflect.Type{
+		reflect.TypeOf(&TraceDatabase{}),
+		reflect.TypeOf(&TraceCache{}),
+		reflect.TypeOf(&TraceServer{}),
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Unexpected construction order %v", types)
+	}
+
+	database, cache, server := trace.Nodes[0], trace.Nodes[1], trace.Nodes[2]
+
+	if len(trace.Roots) != 1 || trace.Roots[0] != server {
+		t.Errorf("Unexpected roots %v", trace.Roots)
+	}
+
+	if len(server.Dependencies) != 2 || server.Dependencies[0] != database || server.Dependencies[1] != cache {
+		t.Errorf("Unexpected dependencies of server %v", server.Dependencies)
+	}
+
+	if len(cache.Dependencies) != 1 || cache.Dependencies[0] != database || len(database.Dependencies) != 0 {
+		t.Errorf("Unexpected dependencies of cache %v", cache.Dependencies)
+	}
+
+	if server.Order != 2 {
+		t.Errorf("Unexpected order %d of server", server.Order)
+	}
+}
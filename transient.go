@@ -0,0 +1,97 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterTransient registers constructors like Register whose values are
+// never shared. Every injection invokes the constructor again, the
+// dependencies of the constructor are still shared.
+//
+//   container.RegisterTransient(NewBuffer)
+//
+// Transient constructors must not return a cleanup function.
+func (container *Container) RegisterTransient(constructors ...interface{}) {
+	for _, _constructor := range constructors {
+		details := newConstructor(_constructor)
+
+		if details.Cleanup {
+			panic(fmt.Errorf("Transient constructor '%s' must not return a cleanup function", details.Function.Type()))
+		}
+
+		details.Transient = true
+
+		container.addConstructors(details)
+	}
+}
+
+// transient reports whether the constructor produces a fresh value on every
+// invocation, which is the case for decorators of transient constructors too.
+func (_resolver *resolver) transient(constructor *constructor) bool {
+	if constructor.Decorator {
+		if base, ok := _resolver.DecoratedBases[constructor]; ok {
+			return base.Transient
+		}
+	}
+
+	return constructor.Transient
+}
+
+// Factory produces fresh values of a transient type T on demand without
+// holding on to the container.
+//
+//   func NewDispatcher(workers *injector.Factory[*Worker]) *Dispatcher {…}
+//
+//   worker, err := dispatcher.workers.New()
+//
+// The constructor of T must be registered with RegisterTransient, otherwise
+// New fails instead of sharing a single value. New is safe for concurrent
+// use.
+type Factory[T any] struct {
+	resolver *resolver
+}
+
+func (factory *Factory[T]) synthesize(_resolver *resolver) error {
+	factory.resolver = _resolver
+	return nil
+}
+
+// New returns a new value of T by invoking its constructor.
+func (factory *Factory[T]) New() (T, error) {
+	var result T
+
+	_resolver := factory.resolver
+
+	_resolver.Lock.Lock()
+	defer _resolver.Lock.Unlock()
+
+	if err := _resolver.Container.checkDisposed(); err != nil {
+		return result, err
+	}
+
+	_type := reflect.TypeOf((*T)(nil)).Elem()
+
+	constructors := _resolver.Container.nearestConstructors(_resolver.Container.findConstructors(_type))
+
+	if err := _resolver.Container.checkSingle(_type, len(constructors)); err != nil {
+		return result, err
+	}
+
+	if !constructors[0].Transient {
+		return result, fmt.Errorf("Constructor '%s' of type '%s' is not transient and cannot be used by a factory", constructors[0].Function.Type(), _type)
+	}
+
+	// Dependencies of the value that were not shared yet are constructed now
+	value, err := _resolver.constructOwned(func() (reflect.Value, error) {
+		return _resolver.provide(constructors[0], _type)
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	reflect.ValueOf(&result).Elem().Set(value)
+
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package injector
+
+import (
+	"testing"
+)
+
+type TransientApp struct {
+	Dispatcher *TransientDispatcher
+	Worker     *TransientWorker
+	Other      *TransientWorker
+}
+
+type TransientDispatcher struct {
+	workers *Factory[*TransientWorker]
+}
+
+type TransientWorker struct {
+	config *TransientConfig
+}
+
+type TransientConfig struct {
+	name string
+}
+
+func NewTransientDispatcher(workers *Factory[*TransientWorker]) *TransientDispatcher {
+	return &TransientDispatcher{
+		workers: workers,
+	}
+}
+
+func NewTransientWorker(config *TransientConfig) *TransientWorker {
+	return &TransientWorker{
+		config: config,
+	}
+}
+
+func TestTransient(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewTransientDispatcher)
+	container.RegisterTransient(NewTransientWorker)
+	container.RegisterInstance(&TransientConfig{name: "config"})
+
+	app := &TransientApp{}
+	container.Resolve(app)
+
+	if app.Worker == app.Other {
+		t.Errorf("Transient worker was shared")
+	}
+
+	first, err := app.Dispatcher.workers.New()
+
+	if err != nil {
+		t.Fatalf("Worker could not be created: %s", err)
+	}
+
+	second, _ := app.Dispatcher.workers.New()
+
+	if first == second || first == app.Worker {
+		t.Errorf("Factory did not create distinct workers")
+	}
+
+	if first.config != second.config {
+		t.Errorf("Dependencies of transient workers were not shared")
+	}
+}
+
+func TestFactoryNotTransient(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(NewTransientDispatcher, NewTransientWorker)
+	container.RegisterInstance(&TransientConfig{})
+
+	app := &struct{ Dispatcher *TransientDispatcher }{}
+	container.Resolve(app)
+
+	if _, err := app.Dispatcher.workers.New(); err == nil {
+		t.Errorf("Factory of shared type did not fail")
+	}
+}
+
+type TransientPoolApp struct {
+	Pool *TransientPool
+}
+
+type TransientPoolConfig struct {
+	Size int `inject:"name=pool_size"`
+}
+
+type TransientPool struct {
+	workers []*TransientWorker
+}
+
+func NewTransientPool(config *TransientPoolConfig, workers *Factory[*TransientWorker]) (*TransientPool, error) {
+	pool := &TransientPool{}
+
+	for i := 0; i < config.Size; i++ {
+		worker, err := workers.New()
+
+		if err != nil {
+			return nil, err
+		}
+
+		pool.workers = append(pool.workers, worker)
+	}
+
+	return pool, nil
+}
+
+func TestTransientPool(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterNamed("pool_size", func() int { return 3 })
+	container.RegisterStruct((*TransientPoolConfig)(nil))
+	container.RegisterTransient(NewTransientWorker)
+	container.RegisterInstance(&TransientConfig{name: "config"})
+	container.Register(NewTransientPool)
+
+	app := &TransientPoolApp{}
+	container.Resolve(app)
+
+	workers := app.Pool.workers
+
+	if len(workers) != 3 {
+		t.Fatalf("Pool was not sized by the named value")
+	}
+
+	if workers[0] == workers[1] || workers[1] == workers[2] || workers[0] == workers[2] {
+		t.Errorf("Workers of pool are not distinct")
+	}
+}
+
+type TransientConnection struct {
+}
+
+type TransientSession struct {
+	connection *TransientConnection
+}
+
+type TransientSessionApp struct {
+	Sessions *Factory[*TransientSession]
+}
+
+func TestFactoryDispose(t *testing.T) {
+	container := NewContainer()
+
+	closed := 0
+
+	container.Register(func() (*TransientConnection, func()) {
+		return &TransientConnection{}, func() { closed++ }
+	})
+	container.RegisterTransient(func(connection *TransientConnection) *TransientSession {
+		return &TransientSession{connection: connection}
+	})
+
+	app := &TransientSessionApp{}
+	container.Resolve(app)
+
+	first, err := app.Sessions.New()
+
+	if err != nil {
+		t.Fatalf("Session could not be created: %s", err)
+	}
+
+	second, _ := app.Sessions.New()
+
+	if first.connection != second.connection {
+		t.Errorf("Connection of sessions was not shared")
+	}
+
+	if err := container.Dispose(); err != nil || closed != 1 {
+		t.Errorf("Cleanup of connection was run %d times by Dispose: %v", closed, err)
+	}
+}
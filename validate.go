@@ -0,0 +1,348 @@
+package injector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// Validate checks that the roots could be resolved without invoking any
+// constructor and returns the error Resolve would panic with. The roots are
+// left untouched.
+//
+//   if err := container.Validate(&App{}, &Admin{}); err != nil {
+//     log.Fatal(err)
+//   }
+//
+// Every root is validated on its own. Errors shared by several roots are
+// reported once along with the types of all roots that run into them.
+//
+// Findings that do not prevent the resolution, like members of groups that
+// are never injected, are reported as diagnostics.
+func (container *Container) Validate(roots ...interface{}) error {
+	return container.validate(roots, false)
+}
+
+// ValidateDeep works like Validate but invokes the constructors registered
+// with RegisterPure, so errors they return or values of the wrong dynamic type
+// are detected as well. A pure constructor is only invoked if all of its
+// dependencies are produced by pure constructors, other constructors are never
+// invoked.
+//
+//   if err := container.ValidateDeep(&App{}); err != nil {
+//     log.Fatal(err)
+//   }
+func (container *Container) ValidateDeep(roots ...interface{}) error {
+	return container.validate(roots, true)
+}
+
+func (container *Container) validate(roots []interface{}, pure bool) error {
+	var messages []string
+
+	var invoked []*constructor
+
+	failures := make(map[string][]error)
+	rootTypes := make(map[string][]reflect.Type)
+
+	if err := checkRoots(roots); err != nil {
+		return err
+	}
+
+	for _, root := range roots {
+		resolver := newResolver(context.Background(), container)
+		resolver.DryRun = true
+		resolver.InvokePure = pure
+
+		if _, err := resolver.resolveFields(root); err != nil {
+			if _, ok := failures[err.Error()]; !ok {
+				messages = append(messages, err.Error())
+			}
+
+			failures[err.Error()] = append(failures[err.Error()], err)
+			rootTypes[err.Error()] = append(rootTypes[err.Error()], reflect.TypeOf(root))
+		}
+
+		invoked = append(invoked, resolver.InvokedConstructors...)
+	}
+
+	if len(roots) == 1 && len(messages) == 1 {
+		return failures[messages[0]][0]
+	}
+
+	if len(messages) > 0 {
+		var errs []error
+
+		for _, message := range messages {
+			errs = append(errs, fmt.Errorf("%w (roots %s)", failures[message][0], formatTypes(rootTypes[message])))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	var types []reflect.Type
+
+	for _, root := range roots {
+		types = append(types, reflect.ValueOf(root).Elem().Type())
+	}
+
+	container.reportOrphanedGroups(types)
+	container.reportEmptySlices(types, invoked)
+	container.reportAmbiguousInterfaces()
+	container.reportUnexportedImplementations(types)
+
+	return nil
+}
+
+// reportAmbiguousInterfaces reports the interfaces requested as single value
+// by any registered constructor, reachable from the roots or not, that would
+// fail with an ambiguity once they are resolved.
+func (container *Container) reportAmbiguousInterfaces() {
+	reported := make(map[reflect.Type]bool)
+
+	for _, constructor := range container.allConstructors() {
+		for _, dependency := range constructor.Dependencies {
+			_type := dependency.Type
+
+			if _type.Kind() != reflect.Interface || dependency.tagged() || reported[_type] {
+				continue
+			}
+
+			// Decorators receive the value they decorate
+			if constructor.Decorator && _type == constructor.ReturnType {
+				continue
+			}
+
+			candidates := container.findConstructors(_type)
+
+			nearest := container.nearestConstructors(candidates)
+
+			if len(candidates) < 2 || len(nearest) == 1 || highestPriority(nearest) != nil {
+				continue
+			}
+
+			reported[_type] = true
+
+			container.report(
+				AmbiguousInterface, _type,
+				"Constructor '%s' takes interface '%s' implemented by %d constructors without selecting one",
+				constructor.Function.Type(), _type, len(candidates),
+			)
+		}
+	}
+}
+
+// reportUnexportedImplementations reports the constructors returning an
+// unexported concrete type that is injected into a constructor or into a field
+// of one of the roots through an interface, but never by that type or its
+// pointer type.
+func (container *Container) reportUnexportedImplementations(rootTypes []reflect.Type) {
+	if !container.strictExports {
+		return
+	}
+
+	direct := make(map[reflect.Type]bool)
+
+	var interfaces []reflect.Type
+
+	consume := func(dependency *dependency) {
+		_type := innerType(dependency.Type)
+		direct[_type] = true
+
+		if _type.Kind() == reflect.Interface {
+			interfaces = append(interfaces, _type)
+		}
+
+		if _type.Kind() == reflect.Ptr {
+			direct[_type.Elem()] = true
+		} else {
+			direct[reflect.PtrTo(_type)] = true
+		}
+	}
+
+	for _, constructor := range container.allConstructors() {
+		for _, dependency := range constructor.Dependencies {
+			consume(dependency)
+		}
+	}
+
+	for _, rootType := range rootTypes {
+		for i := 0; i < rootType.NumField(); i++ {
+			if dependency, err := newFieldDependency(rootType.Field(i)); err == nil {
+				consume(dependency)
+			}
+		}
+	}
+
+	reported := make(map[reflect.Type]bool)
+
+	for _, constructor := range container.allConstructors() {
+		_type := constructor.ReturnType
+
+		if constructor.Decorator || !unexportedType(_type) || direct[_type] || reported[_type] {
+			continue
+		}
+
+		injected := false
+
+		for _, _interface := range interfaces {
+			injected = injected || _type.Implements(_interface)
+		}
+
+		if !injected {
+			continue
+		}
+
+		reported[_type] = true
+
+		container.report(
+			UnexportedImplementation, _type,
+			"Constructor '%s' returns unexported type '%s' which is only injected through interfaces",
+			constructor.Function.Type(), _type,
+		)
+	}
+}
+
+// unexportedType reports whether the type, or the type it points to, is a
+// named concrete type that is not exported.
+func unexportedType(_type reflect.Type) bool {
+	if _type.Kind() == reflect.Ptr {
+		_type = _type.Elem()
+	}
+
+	return _type.Kind() != reflect.Interface && _type.Name() != "" && !token.IsExported(_type.Name())
+}
+
+// reportEmptySlices reports the slices of interfaces injected into a field of
+// one of the roots or into an invoked constructor that have no members at all.
+func (container *Container) reportEmptySlices(rootTypes []reflect.Type, invoked []*constructor) {
+	var dependencies []*dependency
+
+	for _, rootType := range rootTypes {
+		for i := 0; i < rootType.NumField(); i++ {
+			if dependency, err := newFieldDependency(rootType.Field(i)); err == nil {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	for _, constructor := range invoked {
+		dependencies = append(dependencies, constructor.Dependencies...)
+	}
+
+	reported := make(map[reflect.Type]bool)
+
+	for _, dependency := range dependencies {
+		_type := dependency.Type
+
+		if _type.Kind() != reflect.Slice || _type.Elem().Kind() != reflect.Interface || dependency.tagged() || reported[_type] {
+			continue
+		}
+
+		if len(container.filterMembers(_type.Elem(), container.findConstructors(_type.Elem()))) > 0 {
+			continue
+		}
+
+		reported[_type] = true
+
+		container.report(
+			EmptySlice, _type,
+			"Slice of type '%s' is injected but no constructor returns an implementation of '%s'",
+			_type, _type.Elem(),
+		)
+	}
+}
+
+// RegisterPure registers constructors like Register and marks them as free of
+// side effects, so ValidateDeep may invoke them. Pure constructors must not
+// return a cleanup function.
+//
+//   container.RegisterPure(NewRouter, NewConfigParser)
+func (container *Container) RegisterPure(constructors ...interface{}) {
+	for _, _constructor := range constructors {
+		details := newConstructor(_constructor)
+
+		if details.Cleanup {
+			panic(fmt.Errorf("Pure constructor '%s' must not return a cleanup function", details.Function.Type()))
+		}
+
+		details.Pure = true
+
+		container.addConstructors(details)
+	}
+}
+
+// invokesPure reports whether the pure constructor is invoked during a dry
+// run because no placeholder was created while resolving its dependencies.
+func (_resolver *resolver) invokesPure(constructor *constructor, placeholders int) bool {
+	return _resolver.InvokePure && constructor.Pure && !constructor.Decorator && _resolver.Placeholders == placeholders
+}
+
+// placeholder returns the zero value standing in for a value that is not
+// constructed during a dry run.
+func (_resolver *resolver) placeholder(_type reflect.Type) reflect.Value {
+	_resolver.Placeholders++
+	return reflect.Zero(_type)
+}
+
+// formatTypes quotes and joins the types for an error message.
+func formatTypes(types []reflect.Type) string {
+	var quoted []string
+
+	for _, _type := range types {
+		quoted = append(quoted, fmt.Sprintf("'%s'", _type))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// reportOrphanedGroups reports the groups that have members but are neither
+// injected into a constructor nor into a field of one of the roots.
+func (container *Container) reportOrphanedGroups(rootTypes []reflect.Type) {
+	consumed := make(map[string]bool)
+
+	consume := func(dependency *dependency) {
+		consumed[dependency.Group] = true
+		consumed[dependency.GroupNames] = true
+	}
+
+	for _, constructor := range container.allConstructors() {
+		for _, dependency := range constructor.Dependencies {
+			consume(dependency)
+		}
+	}
+
+	for _, keyed := range container.keyedConstructors {
+		for _, dependency := range keyed.Constructor.Dependencies {
+			consume(dependency)
+		}
+	}
+
+	for _, rootType := range rootTypes {
+		for i := 0; i < rootType.NumField(); i++ {
+			if dependency, err := newFieldDependency(rootType.Field(i)); err == nil {
+				consume(dependency)
+			}
+		}
+	}
+
+	reported := make(map[string]bool)
+
+	for _, constructor := range container.allConstructors() {
+		for _, group := range constructor.Groups {
+			if consumed[group] || reported[group] {
+				continue
+			}
+
+			reported[group] = true
+
+			container.report(
+				OrphanedGroupMember, constructor.ReturnType,
+				"Constructor '%s' is a member of group '%s' which is never injected",
+				constructor.Function.Type(), group,
+			)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package injector
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ValidateApp struct {
+	Server *ValidateServer
+}
+
+type ValidateServer struct {
+	handlers []ValidateHandler
+}
+
+type ValidateHandler interface {
+	Handle()
+}
+
+type ValidateStatusHandler struct {
+	path string
+}
+
+func (handler *ValidateStatusHandler) Handle() {
+}
+
+func NewValidateServer(handlers []ValidateHandler) *ValidateServer {
+	return &ValidateServer{
+		handlers: handlers,
+	}
+}
+
+func NewValidateStatusHandler() *ValidateStatusHandler {
+	return &ValidateStatusHandler{}
+}
+
+func TestValidate(t *testing.T) {
+	container := NewContainer()
+
+	constructed := false
+
+	container.Register(func(handlers []ValidateHandler) *ValidateServer {
+		constructed = true
+		return NewValidateServer(handlers)
+	})
+
+	app := &ValidateApp{}
+
+	if err := container.Validate(app); err != nil {
+		t.Errorf("Valid graph was rejected: %s", err)
+	}
+
+	if constructed || app.Server != nil {
+		t.Errorf("Validate constructed the graph")
+	}
+}
+
+func TestValidateMissing(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func(handler ValidateHandler) *ValidateServer {
+		return &ValidateServer{}
+	})
+
+	if err := container.Validate(&ValidateApp{}); err == nil {
+		t.Errorf("Missing dependency was not detected")
+	}
+}
+
+func TestValidateOrphanedGroupMember(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		if diagnostic.Kind == OrphanedGroupMember {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}))
+
+	container.Register(NewValidateServer)
+	container.RegisterGroup("handler", NewValidateStatusHandler)
+
+	if err := container.Validate(&ValidateApp{}); err != nil {
+		t.Fatalf("Valid graph was rejected: %s", err)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Type != reflect.TypeOf(&ValidateStatusHandler{}) {
+		t.Errorf("Orphaned group member was not reported")
+	}
+}
+
+type ValidateAdmin struct {
+	Server *ValidateServer
+}
+
+func TestValidateRoots(t *testing.T) {
+	container := NewContainer()
+
+	container.Register(func(handler ValidateHandler) *ValidateServer {
+		return &ValidateServer{}
+	})
+
+	err := container.Validate(&ValidateApp{}, &ValidateAdmin{})
+
+	if err == nil {
+		t.Fatalf("Missing dependency was not detected")
+	}
+
+	if strings.Count(err.Error(), "ValidateHandler") != 1 {
+		t.Errorf("Shared missing dependency was not reported once: %s", err)
+	}
+
+	if !strings.Contains(err.Error(), "'*injector.ValidateApp', '*injector.ValidateAdmin'") {
+		t.Errorf("Roots of the missing dependency were not reported: %s", err)
+	}
+}
+
+type ValidateDeepApp struct {
+	Server *ValidateServer
+	Router *ValidateRouter
+}
+
+type ValidateRouter struct {
+	handler ValidateHandler
+}
+
+func TestValidateDeep(t *testing.T) {
+	container := NewContainer()
+
+	serverConstructed := false
+	routerConstructed := false
+
+	container.Register(func() *ValidateServer {
+		serverConstructed = true
+		return &ValidateServer{}
+	})
+
+	container.RegisterPure(
+		func(handler ValidateHandler) *ValidateRouter {
+			routerConstructed = true
+			return &ValidateRouter{handler: handler}
+		},
+		NewValidateStatusHandler,
+	)
+
+	if err := container.ValidateDeep(&ValidateDeepApp{}); err != nil {
+		t.Errorf("Valid graph was rejected: %s", err)
+	}
+
+	if !routerConstructed {
+		t.Errorf("Pure constructor was not invoked")
+	}
+
+	if serverConstructed {
+		t.Errorf("Impure constructor was invoked")
+	}
+}
+
+func TestValidateDeepError(t *testing.T) {
+	container := NewContainer()
+
+	container.RegisterPure(func() (*ValidateRouter, error) {
+		return nil, errors.New("invalid route")
+	})
+
+	app := &struct{ Router *ValidateRouter }{}
+
+	if err := container.Validate(app); err != nil {
+		t.Errorf("Pure constructor was invoked by Validate: %s", err)
+	}
+
+	if err := container.ValidateDeep(app); err == nil || !strings.Contains(err.Error(), "invalid route") {
+		t.Errorf("Error of pure constructor was not detected: %v", err)
+	}
+}
+
+func TestValidateEmptySlice(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		if diagnostic.Kind == EmptySlice {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}))
+
+	container.Register(NewValidateServer)
+
+	if err := container.Validate(&ValidateApp{}); err != nil {
+		t.Errorf("Graph with empty slice was rejected: %s", err)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Type != reflect.TypeOf([]ValidateHandler{}) {
+		t.Errorf("Empty slice was not reported: %v", diagnostics)
+	}
+
+	diagnostics = nil
+
+	container.Register(NewValidateStatusHandler)
+
+	if err := container.Validate(&ValidateApp{}); err != nil || len(diagnostics) != 0 {
+		t.Errorf("Slice with members was reported: %v", diagnostics)
+	}
+}
+
+type ValidateAuditHandler struct {
+	path string
+}
+
+func (handler *ValidateAuditHandler) Handle() {
+}
+
+func TestValidateAmbiguousInterface(t *testing.T) {
+	var diagnostics []Diagnostic
+
+	container := NewContainer(WithDiagnostics(func(diagnostic Diagnostic) {
+		if diagnostic.Kind == AmbiguousInterface {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}))
+
+	container.Register(NewValidateServer, NewValidateStatusHandler, func() *ValidateAuditHandler {
+		return &ValidateAuditHandler{}
+	})
+
+	// Not reachable from the root, so only the diagnostic points it out
+	container.Register(func(handler ValidateHandler) *ValidateRouter {
+		return &ValidateRouter{handler: handler}
+	})
+
+	if err := container.Validate(&ValidateApp{}); err != nil {
+		t.Errorf("Valid graph was rejected: %s", err)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Type != reflect.TypeOf((*ValidateHandler)(nil)).Elem() {
+		t.Errorf("Ambiguous interface was not reported: %v", diagnostics)
+	}
+}
@@ -0,0 +1,54 @@
+package injector
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterVariant registers a constructor that is only used while the
+// container runs in the given mode, see WithMode. Registering a variant for
+// each mode replaces conditional registrations.
+//
+//   container.RegisterVariant(ModeProduction, NewStripeGateway)
+//   container.RegisterVariant(ModeTesting, NewFakeGateway)
+//
+// Modes are compared with ==, so the mode must be comparable.
+func (container *Container) RegisterVariant(mode interface{}, _constructor interface{}) {
+	details := newConstructor(_constructor)
+
+	if mode == nil || !reflect.TypeOf(mode).Comparable() {
+		panic(fmt.Errorf("Mode '%v' of variant '%s' must be a comparable value", mode, details.Function.Type()))
+	}
+
+	details.Variant = mode
+
+	container.addConstructors(details)
+}
+
+// WithMode selects the mode whose variants are used by the container.
+// Variants of other modes are ignored, constructors registered without a mode
+// are always used.
+//
+//   injector.NewContainer(injector.WithMode(ModeProduction))
+func WithMode(mode interface{}) Option {
+	return func(container *Container) {
+		container.mode = mode
+	}
+}
+
+// activeVariant reports whether the constructor is not a variant or a
+// variant of the selected mode.
+func (container *Container) activeVariant(constructor *constructor) bool {
+	return constructor.Variant == nil || constructor.Variant == container.mode
+}
+
+// inactiveVariant returns a variant of another mode returning the type.
+func (container *Container) inactiveVariant(_type reflect.Type) *constructor {
+	for _, constructor := range container.allConstructors() {
+		if !container.activeVariant(constructor) && constructor.ReturnType.AssignableTo(_type) {
+			return constructor
+		}
+	}
+
+	return nil
+}
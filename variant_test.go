@@ -0,0 +1,77 @@
+package injector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type VariantMode int
+
+const (
+	VariantProduction VariantMode = iota
+	VariantTesting
+	VariantStaging
+)
+
+type VariantApp struct {
+	Gateway VariantGateway
+}
+
+type VariantGateway interface {
+	Charge() string
+}
+
+type VariantStripeGateway struct {
+	name string
+}
+
+func (gateway *VariantStripeGateway) Charge() string {
+	return "stripe"
+}
+
+type VariantFakeGateway struct {
+	name string
+}
+
+func (gateway *VariantFakeGateway) Charge() string {
+	return "fake"
+}
+
+func registerVariantGateways(container *Container) {
+	container.RegisterVariant(VariantProduction, func() *VariantStripeGateway { return &VariantStripeGateway{} })
+	container.RegisterVariant(VariantTesting, func() *VariantFakeGateway { return &VariantFakeGateway{} })
+}
+
+func TestVariant(t *testing.T) {
+	container := NewContainer(WithMode(VariantProduction))
+	registerVariantGateways(container)
+
+	app := &VariantApp{}
+	container.Resolve(app)
+
+	if app.Gateway.Charge() != "stripe" {
+		t.Errorf("Variant of production mode could not be resolved")
+	}
+
+	container = NewContainer(WithMode(VariantTesting))
+	registerVariantGateways(container)
+
+	app = &VariantApp{}
+	container.Resolve(app)
+
+	if app.Gateway.Charge() != "fake" {
+		t.Errorf("Variant of testing mode could not be resolved")
+	}
+}
+
+func TestVariantMissing(t *testing.T) {
+	container := NewContainer(WithMode(VariantStaging))
+	registerVariantGateways(container)
+
+	err := container.ResolveContext(context.Background(), &VariantApp{})
+
+	if err == nil || !strings.Contains(err.Error(), "No variant") {
+		t.Errorf("Missing variant was not detected: %v", err)
+	}
+}